@@ -0,0 +1,108 @@
+package http
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/ryanCool/ethService/domain"
+	"github.com/ryanCool/ethService/helper"
+	"net/http"
+)
+
+// AbiHandler represent the admin httphandler for contract abi registry
+type AbiHandler struct {
+	AUseCase domain.AbiUseCase
+}
+
+func NewAbiHandler(e *gin.Engine, au domain.AbiUseCase, adminToken string) {
+	handler := &AbiHandler{
+		AUseCase: au,
+	}
+
+	ag := e.Group("abis", helper.AdminAuthMiddleware(adminToken))
+
+	ag.GET("/", handler.ListAbis)
+	ag.GET("/:addr", handler.GetAbi)
+	ag.PUT("/:addr", handler.SaveAbi)
+	ag.DELETE("/:addr", handler.DeleteAbi)
+}
+
+func (a *AbiHandler) ListAbis(ctx *gin.Context) {
+	abis, err := a.AUseCase.List(ctx)
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{"abis": abis})
+}
+
+func (a *AbiHandler) GetAbi(ctx *gin.Context) {
+	address := ctx.Param("addr")
+	if !common.IsHexAddress(address) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	contractAbi, err := a.AUseCase.Get(ctx, common.HexToAddress(address).String())
+	if err == domain.ErrAbiNotExist {
+		helper.RespondWithError(ctx, http.StatusNotFound, err)
+		return
+	}
+
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, contractAbi)
+}
+
+// SaveAbi upload abi json of contract , abi of the same contract is replaced
+func (a *AbiHandler) SaveAbi(ctx *gin.Context) {
+	address := ctx.Param("addr")
+	if !common.IsHexAddress(address) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	contractAbi := &domain.ContractAbi{}
+	if err := ctx.ShouldBindJSON(contractAbi); err != nil {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	contractAbi.Address = common.HexToAddress(address).String()
+
+	err := a.AUseCase.Save(ctx, contractAbi)
+	if err == domain.ErrInvalidAbi {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, contractAbi)
+}
+
+func (a *AbiHandler) DeleteAbi(ctx *gin.Context) {
+	address := ctx.Param("addr")
+	if !common.IsHexAddress(address) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	err := a.AUseCase.Delete(ctx, common.HexToAddress(address).String())
+	if err == domain.ErrAbiNotExist {
+		helper.RespondWithError(ctx, http.StatusNotFound, err)
+		return
+	}
+
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"github.com/ryanCool/ethService/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresAbiRepository struct {
+	Db *gorm.DB
+}
+
+// NewPostgresAbiRepository will create an object that represent the abi.Repository interface
+func NewPostgresAbiRepository(db *gorm.DB) domain.AbiRepository {
+	return &postgresAbiRepository{db}
+}
+
+// Save insert abi of contract or replace the existing one
+func (p *postgresAbiRepository) Save(ctx context.Context, contractAbi *domain.ContractAbi) error {
+	return p.Db.Table("eth.contract_abis").Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "address"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"name":       contractAbi.Name,
+			"abi":        gorm.Expr("EXCLUDED.abi"),
+			"updated_at": gorm.Expr("date_part('epoch'::text, now()) * (1000)::double precision"),
+		}),
+	}).Create(&contractAbi).Error
+}
+
+func (p *postgresAbiRepository) Get(ctx context.Context, address string) (*domain.ContractAbi, error) {
+	var res *domain.ContractAbi
+	if err := p.Db.Table("eth.contract_abis").Where("address = ?", address).First(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *postgresAbiRepository) Delete(ctx context.Context, address string) error {
+	d := p.Db.Table("eth.contract_abis").Where("address = ?", address).Delete(&domain.ContractAbi{})
+	if d.Error != nil {
+		return d.Error
+	}
+
+	if d.RowsAffected != 1 {
+		return domain.ErrAbiNotExist
+	}
+
+	return nil
+}
+
+func (p *postgresAbiRepository) List(ctx context.Context) ([]domain.ContractAbi, error) {
+	var res []domain.ContractAbi
+	if err := p.Db.Table("eth.contract_abis").Order("address").Find(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
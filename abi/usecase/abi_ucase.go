@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/rs/zerolog/log"
+	"github.com/ryanCool/ethService/domain"
+	"gorm.io/gorm"
+	"time"
+)
+
+type abiUseCase struct {
+	repo           domain.AbiRepository
+	contextTimeout time.Duration
+}
+
+func NewAbiUseCase(a domain.AbiRepository, timeout time.Duration) domain.AbiUseCase {
+	return &abiUseCase{
+		repo:           a,
+		contextTimeout: timeout,
+	}
+}
+
+// Save validate abi json and save it for contract
+func (au *abiUseCase) Save(ctx context.Context, contractAbi *domain.ContractAbi) error {
+	if _, err := abi.JSON(bytes.NewReader(contractAbi.Abi)); err != nil {
+		return domain.ErrInvalidAbi
+	}
+
+	return au.repo.Save(ctx, contractAbi)
+}
+
+func (au *abiUseCase) Get(ctx context.Context, address string) (*domain.ContractAbi, error) {
+	contractAbi, err := au.repo.Get(ctx, address)
+	if err == gorm.ErrRecordNotFound {
+		return nil, domain.ErrAbiNotExist
+	}
+
+	if err != nil {
+		log.Err(err).Msg("get abi by address fail")
+		return nil, err
+	}
+
+	return contractAbi, nil
+}
+
+func (au *abiUseCase) Delete(ctx context.Context, address string) error {
+	return au.repo.Delete(ctx, address)
+}
+
+func (au *abiUseCase) List(ctx context.Context) ([]domain.ContractAbi, error) {
+	return au.repo.List(ctx)
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/ryanCool/ethService/domain"
+	"github.com/ryanCool/ethService/helper"
+	"net/http"
+	"strconv"
+)
+
+// BalanceHandler represent the httphandler for token balance
+type BalanceHandler struct {
+	BUseCase domain.BalanceUseCase
+	CUseCase domain.CheckpointUseCase
+}
+
+func NewBalanceHandler(e *gin.Engine, bu domain.BalanceUseCase, cu domain.CheckpointUseCase) {
+	handler := &BalanceHandler{
+		BUseCase: bu,
+		CUseCase: cu,
+	}
+
+	ag := e.Group("address")
+
+	ag.GET("/:addr/balances", handler.ListAddressBalances)
+
+	tg := e.Group("tokens")
+
+	tg.GET("/:contract/holders", handler.ListTokenHolders)
+}
+
+// ListAddressBalances list token balances of an address
+func (a *BalanceHandler) ListAddressBalances(ctx *gin.Context) {
+	address := ctx.Param("addr")
+	if !common.IsHexAddress(address) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	balances, err := a.BUseCase.ListByHolder(ctx, common.HexToAddress(address).String())
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	a.respondBalances(ctx, balances)
+}
+
+// ListTokenHolders list top holders of a token by balance
+func (a *BalanceHandler) ListTokenHolders(ctx *gin.Context) {
+	contract := ctx.Param("contract")
+	if !common.IsHexAddress(contract) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	top, _ := strconv.Atoi(ctx.Query("top"))
+	if top == 0 {
+		//set default to 20
+		top = 20
+	}
+
+	if top < 0 || top > 100 {
+		ctx.JSON(http.StatusBadRequest, "top should be 0~100")
+		return
+	}
+
+	holders, err := a.BUseCase.ListHolders(ctx, common.HexToAddress(contract).String(), top)
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	a.respondBalances(ctx, holders)
+}
+
+// respondBalances respond balances with the last block applied to ledger , balances are as of that block
+func (a *BalanceHandler) respondBalances(ctx *gin.Context, balances []domain.TokenBalance) {
+	res := map[string]interface{}{"balances": balances}
+
+	checkpoint, err := a.CUseCase.Get(ctx, domain.CheckpointTokenBalance)
+	if err != nil && err != domain.ErrCheckpointNotExist {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if checkpoint != nil {
+		res["block_num"] = checkpoint.BlockNum
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
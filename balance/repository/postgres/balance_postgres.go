@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ryanCool/ethService/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// applyDeltaSQL adds signed transfer amounts of a block range to balances , mint and burn
+// counterparty (zero address) is not a holder . Sign is 1 to apply and -1 to revert.
+// Revert recomputes deltas from eth.token_transfers , so transfers of an applied block must stay in the table
+// until the block is reverted . Blocks are reverted before they are deleted (reorg , repair) , transfers deleted
+// first would stay counted in balances.
+const applyDeltaSQL = `INSERT INTO eth.token_balances (token, holder, balance)
+	SELECT token, holder, SUM(delta) * @sign FROM (
+		SELECT token, to_address AS holder, value AS delta FROM eth.token_transfers
+			WHERE block_num >= @from AND block_num <= @to AND to_address <> @zero
+		UNION ALL
+		SELECT token, from_address AS holder, -value AS delta FROM eth.token_transfers
+			WHERE block_num >= @from AND block_num <= @to AND from_address <> @zero
+	) d GROUP BY token, holder
+	ON CONFLICT (token, holder) DO UPDATE SET balance = eth.token_balances.balance + EXCLUDED.balance,
+		updated_at = date_part('epoch'::text, now()) * (1000)::double precision`
+
+type postgresBalanceRepository struct {
+	Db *gorm.DB
+}
+
+// NewPostgresBalanceRepository will create an object that represent the balance.Repository interface
+func NewPostgresBalanceRepository(db *gorm.DB) domain.BalanceRepository {
+	return &postgresBalanceRepository{db}
+}
+
+// Apply add transfers of blocks fromBlock to toBlock to balances and move ledger checkpoint to toBlock in one transaction
+func (p *postgresBalanceRepository) Apply(ctx context.Context, fromBlock uint64, toBlock uint64, blockHash string) error {
+	return p.Db.Transaction(func(tx *gorm.DB) error {
+		if err := applyDelta(tx, fromBlock, toBlock, 1); err != nil {
+			return err
+		}
+
+		return saveCheckpoint(tx, toBlock, blockHash)
+	})
+}
+
+// RevertTo subtract transfers of applied blocks after blockNum and move ledger checkpoint back to blockNum .
+// Nothing is done when ledger never went past blockNum.
+func (p *postgresBalanceRepository) RevertTo(ctx context.Context, blockNum uint64) error {
+	return p.Db.Transaction(func(tx *gorm.DB) error {
+		var checkpoint *domain.SyncCheckpoint
+		err := tx.Table("eth.sync_checkpoints").Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("name = ?", domain.CheckpointTokenBalance).First(&checkpoint).Error
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if checkpoint.BlockNum <= blockNum {
+			return nil
+		}
+
+		if err = applyDelta(tx, blockNum+1, checkpoint.BlockNum, -1); err != nil {
+			return err
+		}
+
+		var blockHash string
+		if err = tx.Table("eth.blocks").Select("block_hash").Where("block_num = ?", blockNum).Scan(&blockHash).Error; err != nil {
+			return err
+		}
+
+		return saveCheckpoint(tx, blockNum, blockHash)
+	})
+}
+
+func applyDelta(tx *gorm.DB, fromBlock uint64, toBlock uint64, sign int) error {
+	return tx.Exec(applyDeltaSQL, map[string]interface{}{
+		"from": fromBlock,
+		"to":   toBlock,
+		"zero": common.Address{}.String(),
+		"sign": sign,
+	}).Error
+}
+
+func saveCheckpoint(tx *gorm.DB, blockNum uint64, blockHash string) error {
+	return tx.Table("eth.sync_checkpoints").Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "name"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"block_num":  blockNum,
+			"block_hash": blockHash,
+			"updated_at": gorm.Expr("date_part('epoch'::text, now()) * (1000)::double precision"),
+		}),
+	}).Create(&domain.SyncCheckpoint{
+		Name:      domain.CheckpointTokenBalance,
+		BlockNum:  blockNum,
+		BlockHash: blockHash,
+	}).Error
+}
+
+// ListByHolder list non zero balances of holder
+func (p *postgresBalanceRepository) ListByHolder(ctx context.Context, holder string) ([]domain.TokenBalance, error) {
+	var res []domain.TokenBalance
+	if err := p.Db.Table("eth.token_balances").Where("holder = ? AND balance <> 0", holder).Order("token").Find(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListHolders list top holders of token by balance
+func (p *postgresBalanceRepository) ListHolders(ctx context.Context, token string, top int) ([]domain.TokenBalance, error) {
+	var res []domain.TokenBalance
+	if err := p.Db.Table("eth.token_balances").Where("token = ? AND balance > 0", token).Order("balance desc, holder").Limit(top).Find(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"github.com/ryanCool/ethService/domain"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"os"
+	"reflect"
+	"testing"
+)
+
+const (
+	testToken = "0x00000000000000000000000000000000000000c1"
+	testAlice = "0x00000000000000000000000000000000000000a1"
+	testBob   = "0x00000000000000000000000000000000000000b2"
+	zero      = "0x0000000000000000000000000000000000000000"
+
+	// testBlockNum is far above mainnet height , fixtures do not collide with indexed blocks
+	testBlockNum = 9000000000
+)
+
+// openTestDb open postgres of TEST_DATABASE_DSN with schema of devenv , test runs in a transaction rolled back on cleanup
+func openTestDb(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set , ledger sql needs postgres")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seed, err := os.ReadFile("../../../devenv/db_seed.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.Exec(string(seed)).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tx := db.Begin()
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+// seedTransfers store two blocks , alice mints 100 in the first and sends 30 to bob in the second
+func seedTransfers(t *testing.T, tx *gorm.DB) {
+	stmts := []string{
+		`INSERT INTO eth.blocks (block_num, block_hash) VALUES (@n, '0xb1'), (@next, '0xb2')`,
+		`INSERT INTO eth.transactions (block_hash, block_num, tx_index, tx_hash) VALUES ('0xb1', @n, 0, '0xt1'), ('0xb2', @next, 0, '0xt2')`,
+		`INSERT INTO eth.token_transfers (tx_hash, block_hash, block_num, tx_index, log_index, token, from_address, to_address, value) VALUES
+			('0xt1', '0xb1', @n, 0, 0, @token, @zero, @alice, 100),
+			('0xt2', '0xb2', @next, 0, 0, @token, @alice, @bob, 30)`,
+	}
+	for _, stmt := range stmts {
+		err := tx.Exec(stmt, map[string]interface{}{"n": testBlockNum, "next": testBlockNum + 1, "token": testToken, "zero": zero, "alice": testAlice, "bob": testBob}).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func balancesOf(t *testing.T, tx *gorm.DB) map[string]string {
+	var rows []domain.TokenBalance
+	if err := tx.Table("eth.token_balances").Where("token = ?", testToken).Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	res := map[string]string{}
+	for _, row := range rows {
+		res[row.Holder] = row.Balance
+	}
+	return res
+}
+
+func ledgerCheckpoint(t *testing.T, tx *gorm.DB) uint64 {
+	var checkpoint domain.SyncCheckpoint
+	if err := tx.Table("eth.sync_checkpoints").Where("name = ?", domain.CheckpointTokenBalance).First(&checkpoint).Error; err != nil {
+		t.Fatal(err)
+	}
+	return checkpoint.BlockNum
+}
+
+func TestApplyThenRevert(t *testing.T) {
+	tx := openTestDb(t)
+	seedTransfers(t, tx)
+	repo := NewPostgresBalanceRepository(tx)
+	ctx := context.Background()
+
+	steps := []struct {
+		name       string
+		run        func() error
+		checkpoint uint64
+		want       map[string]string
+	}{
+		{"apply both blocks", func() error { return repo.Apply(ctx, testBlockNum, testBlockNum+1, "0xb2") },
+			testBlockNum + 1, map[string]string{testAlice: "70", testBob: "30"}},
+		{"revert second block", func() error { return repo.RevertTo(ctx, testBlockNum) },
+			testBlockNum, map[string]string{testAlice: "100", testBob: "0"}},
+		{"revert again is no-op", func() error { return repo.RevertTo(ctx, testBlockNum) },
+			testBlockNum, map[string]string{testAlice: "100", testBob: "0"}},
+		{"revert first block", func() error { return repo.RevertTo(ctx, testBlockNum-1) },
+			testBlockNum - 1, map[string]string{testAlice: "0", testBob: "0"}},
+	}
+
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s : %v", step.name, err)
+		}
+		if got := ledgerCheckpoint(t, tx); got != step.checkpoint {
+			t.Fatalf("%s : checkpoint %d , want %d", step.name, got, step.checkpoint)
+		}
+		if got := balancesOf(t, tx); !reflect.DeepEqual(got, step.want) {
+			t.Fatalf("%s : balances %v , want %v", step.name, got, step.want)
+		}
+	}
+}
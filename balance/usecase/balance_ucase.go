@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"github.com/ryanCool/ethService/domain"
+	"time"
+)
+
+type balanceUseCase struct {
+	repo           domain.BalanceRepository
+	contextTimeout time.Duration
+}
+
+func NewBalanceUseCase(a domain.BalanceRepository, timeout time.Duration) domain.BalanceUseCase {
+	return &balanceUseCase{
+		repo:           a,
+		contextTimeout: timeout,
+	}
+}
+
+func (bu *balanceUseCase) Apply(ctx context.Context, fromBlock uint64, toBlock uint64, blockHash string) error {
+	return bu.repo.Apply(ctx, fromBlock, toBlock, blockHash)
+}
+
+func (bu *balanceUseCase) RevertTo(ctx context.Context, blockNum uint64) error {
+	return bu.repo.RevertTo(ctx, blockNum)
+}
+
+func (bu *balanceUseCase) ListByHolder(ctx context.Context, holder string) ([]domain.TokenBalance, error) {
+	return bu.repo.ListByHolder(ctx, holder)
+}
+
+func (bu *balanceUseCase) ListHolders(ctx context.Context, token string, top int) ([]domain.TokenBalance, error) {
+	return bu.repo.ListHolders(ctx, token, top)
+}
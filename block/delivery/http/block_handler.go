@@ -22,8 +22,45 @@ func NewBlockHandler(e *gin.Engine, ds domain.BlockUseCase) {
 
 	dg.GET("/:id", handler.GetBlock)
 	dg.GET("/", handler.ListBlock)
+	dg.GET("/gaps", handler.GetGapReport)
 }
 
+// GetGapReport report missing and partially written blocks
+func (a *BlockHandler) GetGapReport(ctx *gin.Context) {
+	fromBlock, err := strconv.ParseUint(ctx.DefaultQuery("from_block", "0"), 10, 64)
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	toBlock, err := strconv.ParseUint(ctx.DefaultQuery("to_block", "0"), 10, 64)
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+	if limit == 0 {
+		//set default to 100
+		limit = 100
+	}
+
+	if limit < 0 || limit > 1000 {
+		ctx.JSON(http.StatusBadRequest, "limit should be 0~1000")
+		return
+	}
+
+	report, err := a.BUseCase.GapReport(ctx, fromBlock, toBlock, limit)
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// ListBlock list blocks in block number order , newest first by default .
+// Response carries next cursor when more blocks are left.
 func (a *BlockHandler) ListBlock(ctx *gin.Context) {
 	limit := ctx.Query("limit")
 	iLimit, _ := strconv.Atoi(limit)
@@ -32,31 +69,75 @@ func (a *BlockHandler) ListBlock(ctx *gin.Context) {
 		iLimit = 20
 	}
 
-	if iLimit > 100 {
+	if iLimit < 0 || iLimit > 100 {
 		ctx.JSON(http.StatusBadRequest, "limit should be 0~100")
 		return
 	}
 
-	results, err := a.BUseCase.List(ctx, iLimit)
+	filter := &domain.BlockFilter{Limit: iLimit}
+	switch ctx.DefaultQuery("order", "desc") {
+	case "desc":
+		filter.Desc = true
+	case "asc":
+	default:
+		ctx.JSON(http.StatusBadRequest, "order should be asc or desc")
+		return
+	}
+
+	bounds := map[string]**uint64{
+		"from_block": &filter.FromBlock,
+		"to_block":   &filter.ToBlock,
+		"from_time":  &filter.FromTime,
+		"to_time":    &filter.ToTime,
+	}
+	for key, bound := range bounds {
+		v := ctx.Query(key)
+		if v == "" {
+			continue
+		}
+
+		n, err := helper.ParseUint(v)
+		if err != nil {
+			helper.RespondWithError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		*bound = &n
+	}
+
+	if cursor := ctx.Query("cursor"); cursor != "" {
+		position, err := helper.DecodeCursor(cursor, 1)
+		if err != nil {
+			helper.RespondWithError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		filter.AfterBlock = &position[0]
+	}
+
+	results, err := a.BUseCase.List(ctx, filter)
 	if err != nil {
 		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, map[string]interface{}{"blocks": results})
+	res := map[string]interface{}{"blocks": results}
+	if len(results) == filter.Limit {
+		res["next"] = helper.EncodeCursor(results[len(results)-1].BlockNum)
+	}
+
+	ctx.JSON(http.StatusOK, res)
 }
 
+// GetBlock get block by decimal or 0x hex number , 0x block hash , or tag latest / latest-stable / safe / finalized
 func (a *BlockHandler) GetBlock(ctx *gin.Context) {
-	blockNum := ctx.Param("id")
-	iBlockNum, err := strconv.Atoi(blockNum)
-	if err != nil {
+	block, err := a.BUseCase.GetByID(ctx, ctx.Param("id"))
+	if err == domain.ErrInvalidBlockID {
 		helper.RespondWithError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	block, err := a.BUseCase.GetByNumber(ctx, uint64(iBlockNum))
 	if err == domain.ErrBlockNotExist {
 		helper.RespondWithError(ctx, http.StatusNotFound, err)
+		return
 	}
 
 	if err != nil {
@@ -15,17 +15,99 @@ func NewPostgresBlockRepository(db *gorm.DB) domain.BlockRepository {
 	return &postgresBlockRepository{db}
 }
 
+// Create insert block with its uncles and withdrawals in one transaction
 func (p *postgresBlockRepository) Create(ctx context.Context, block *domain.BlockDb) error {
-	return p.Db.Table("eth.blocks").Create(&block).Error
+	return p.Db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("eth.blocks").Create(&block).Error; err != nil {
+			return err
+		}
+
+		if len(block.Uncles) > 0 {
+			if err := tx.Table("eth.uncles").Create(&block.Uncles).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(block.Withdrawals) > 0 {
+			if err := tx.Table("eth.withdrawals").Create(&block.Withdrawals).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ListUncles list uncles of block in inclusion order
+func (p *postgresBlockRepository) ListUncles(ctx context.Context, blockHash string) ([]domain.Uncle, error) {
+	var res []domain.Uncle
+	if err := p.Db.Table("eth.uncles").Where("block_hash = ?", blockHash).Order("uncle_index").Find(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListWithdrawals list withdrawals of block in withdrawal index order
+func (p *postgresBlockRepository) ListWithdrawals(ctx context.Context, blockHash string) ([]domain.Withdrawal, error) {
+	var res []domain.Withdrawal
+	if err := p.Db.Table("eth.withdrawals").Where("block_hash = ?", blockHash).Order("withdrawal_index").Find(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
 func (p *postgresBlockRepository) DeleteByNum(ctx context.Context, blockNum uint64) error {
 	return p.Db.Table("eth.blocks").Where("block_num = ?", blockNum).Delete(&domain.BlockDb{}).Error
 }
 
-func (p *postgresBlockRepository) List(ctx context.Context, limit int) ([]domain.BlockDb, error) {
+// Reorg delete every block after the common ancestor and record the reorg event in one transaction ,
+// old fork may be longer than new head . Transactions , receipts and logs of orphaned blocks are dropped by cascade.
+// Replacement blocks are indexed afterwards , so readers see those heights missing until they are written again.
+// Caller holds the sync cursor above the ancestor , no worker writes an orphaned block back after delete.
+func (p *postgresBlockRepository) Reorg(ctx context.Context, event *domain.ReorgEvent) error {
+	return p.Db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("eth.blocks").Where("block_num > ?", event.AncestorNum).Delete(&domain.BlockDb{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Table("eth.reorg_events").Create(&event).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (p *postgresBlockRepository) List(ctx context.Context, filter *domain.BlockFilter) ([]domain.BlockDb, error) {
+	q := p.Db.Table("eth.blocks")
+	if filter.FromBlock != nil {
+		q = q.Where("block_num >= ?", *filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		q = q.Where("block_num <= ?", *filter.ToBlock)
+	}
+	if filter.FromTime != nil {
+		q = q.Where("block_time >= ?", *filter.FromTime)
+	}
+	if filter.ToTime != nil {
+		q = q.Where("block_time <= ?", *filter.ToTime)
+	}
+
+	order := "block_num"
+	if filter.Desc {
+		order = "block_num desc"
+	}
+
+	if filter.AfterBlock != nil {
+		if filter.Desc {
+			q = q.Where("block_num < ?", *filter.AfterBlock)
+		} else {
+			q = q.Where("block_num > ?", *filter.AfterBlock)
+		}
+	}
+
 	var res []domain.BlockDb
-	if err := p.Db.Table("eth.blocks").Limit(limit).Order("block_num desc").Find(&res).Error; err != nil {
+	if err := q.Limit(filter.Limit).Order(order).Find(&res).Error; err != nil {
 		return nil, err
 	}
 	return res, nil
@@ -39,6 +121,59 @@ func (p *postgresBlockRepository) GetByNumber(ctx context.Context, blockNum uint
 	return res, nil
 }
 
+func (p *postgresBlockRepository) GetByHash(ctx context.Context, blockHash string) (*domain.BlockDb, error) {
+	var res *domain.BlockDb
+	if err := p.Db.Table("eth.blocks").Where("block_hash = ?", blockHash).First(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *postgresBlockRepository) GetLatestStable(ctx context.Context) (*domain.BlockDb, error) {
+	var res *domain.BlockDb
+	if err := p.Db.Table("eth.blocks").Where("stable = ?", true).Order("block_num desc").First(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetMinNumber returns the lowest block number not less than fromBlock , nil if no block
+func (p *postgresBlockRepository) GetMinNumber(ctx context.Context, fromBlock uint64) (*uint64, error) {
+	var res *uint64
+	if err := p.Db.Table("eth.blocks").Select("MIN(block_num)").Where("block_num >= ?", fromBlock).Scan(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListGaps lists ranges of block number missing after stored blocks from fromBlock to toBlock ,
+// next stored block is looked up by index so a gap running past toBlock is still reported whole
+func (p *postgresBlockRepository) ListGaps(ctx context.Context, fromBlock uint64, toBlock uint64, limit int) ([]domain.BlockGap, error) {
+	var res []domain.BlockGap
+	if err := p.Db.Raw(`SELECT b.block_num + 1 AS from_block, n.next_num - 1 AS to_block FROM eth.blocks b,
+		LATERAL (SELECT MIN(block_num) AS next_num FROM eth.blocks WHERE block_num > b.block_num) n
+		WHERE b.block_num BETWEEN ? AND ? AND n.next_num > b.block_num + 1
+		ORDER BY b.block_num LIMIT ?`, fromBlock, toBlock, limit).Scan(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListIncomplete lists blocks from fromBlock to toBlock whose transactions or receipts are only partially written
+func (p *postgresBlockRepository) ListIncomplete(ctx context.Context, fromBlock uint64, toBlock uint64, limit int) ([]uint64, error) {
+	var res []uint64
+	if err := p.Db.Raw(`SELECT b.block_num FROM eth.blocks b
+		LEFT JOIN eth.transactions t ON t.block_hash = b.block_hash
+		LEFT JOIN eth.receipts r ON r.tx_hash = t.tx_hash
+		WHERE b.block_num BETWEEN ? AND ?
+		GROUP BY b.block_num, b.tx_count
+		HAVING COUNT(t.tx_hash) <> b.tx_count OR COUNT(r.tx_hash) <> COUNT(t.tx_hash)
+		ORDER BY b.block_num LIMIT ?`, fromBlock, toBlock, limit).Scan(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func (p *postgresBlockRepository) SetStable(ctx context.Context, blockNum uint64, stable bool) error {
 	d := p.Db.Table("eth.blocks").Where("block_num = ?", blockNum).Update("stable", stable)
 	if d.Error != nil {
@@ -2,22 +2,28 @@ package usecase
 
 import (
 	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/rs/zerolog/log"
 	"github.com/ryanCool/ethService/domain"
 	"gorm.io/gorm"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type blockUseCase struct {
 	repo             domain.BlockRepository
 	transactionUcase domain.TransactionUseCase
+	checkpointUcase  domain.CheckpointUseCase
 	contextTimeout   time.Duration
 }
 
-func NewBlockUseCase(a domain.BlockRepository, t domain.TransactionUseCase, timeout time.Duration) domain.BlockUseCase {
+func NewBlockUseCase(a domain.BlockRepository, t domain.TransactionUseCase, c domain.CheckpointUseCase, timeout time.Duration) domain.BlockUseCase {
 	return &blockUseCase{
 		repo:             a,
 		transactionUcase: t,
+		checkpointUcase:  c,
 		contextTimeout:   timeout,
 	}
 }
@@ -30,13 +36,75 @@ func (bu *blockUseCase) DeleteByNum(ctx context.Context, blockNum uint64) error
 	return bu.repo.DeleteByNum(ctx, blockNum)
 }
 
+func (bu *blockUseCase) Reorg(ctx context.Context, event *domain.ReorgEvent) error {
+	return bu.repo.Reorg(ctx, event)
+}
+
 func (bu *blockUseCase) SetStable(ctx context.Context, blockNum uint64, stable bool) error {
 	return bu.repo.SetStable(ctx, blockNum, stable)
 }
 
-//List list latest limit block
-func (bu *blockUseCase) List(ctx context.Context, limit int) ([]domain.BlockDb, error) {
-	return bu.repo.List(ctx, limit)
+//List list blocks matching filter , page by page
+func (bu *blockUseCase) List(ctx context.Context, filter *domain.BlockFilter) ([]domain.BlockDb, error) {
+	return bu.repo.List(ctx, filter)
+}
+
+// GapReport reports missing block ranges and partially written blocks from fromBlock to toBlock .
+// fromBlock 0 means starting from the lowest stored block . The window is cut to domain.GapReportMaxBlockNum blocks ,
+// toBlock 0 means the whole window.
+func (bu *blockUseCase) GapReport(ctx context.Context, fromBlock uint64, toBlock uint64, limit int) (*domain.GapReport, error) {
+	report := &domain.GapReport{
+		MissingRanges:    []domain.BlockGap{},
+		IncompleteBlocks: []uint64{},
+	}
+
+	minNum, err := bu.repo.GetMinNumber(ctx, fromBlock)
+	if err != nil {
+		log.Err(err).Msg("get min block_num fail")
+		return nil, err
+	}
+
+	//nothing stored yet , no way to tell the gap
+	if minNum == nil {
+		return report, nil
+	}
+
+	report.FromBlock = fromBlock
+	if fromBlock == 0 {
+		report.FromBlock = *minNum
+	}
+	report.ToBlock = report.FromBlock + domain.GapReportMaxBlockNum - 1
+	if toBlock > 0 && toBlock < report.ToBlock {
+		report.ToBlock = toBlock
+	}
+
+	if report.ToBlock < report.FromBlock {
+		return report, nil
+	}
+
+	if fromBlock > 0 && *minNum > fromBlock {
+		report.MissingRanges = append(report.MissingRanges, domain.BlockGap{FromBlock: fromBlock, ToBlock: *minNum - 1})
+	}
+
+	gaps, err := bu.repo.ListGaps(ctx, report.FromBlock, report.ToBlock, limit)
+	if err != nil {
+		log.Err(err).Msg("list block gaps fail")
+		return nil, err
+	}
+	report.MissingRanges = append(report.MissingRanges, gaps...)
+
+	for _, gap := range report.MissingRanges {
+		report.MissingBlockNum += gap.ToBlock - gap.FromBlock + 1
+	}
+
+	incomplete, err := bu.repo.ListIncomplete(ctx, report.FromBlock, report.ToBlock, limit)
+	if err != nil {
+		log.Err(err).Msg("list incomplete blocks fail")
+		return nil, err
+	}
+	report.IncompleteBlocks = append(report.IncompleteBlocks, incomplete...)
+
+	return report, nil
 }
 
 func (bu *blockUseCase) GetByNumber(ctx context.Context, blockNum uint64) (*domain.Block, error) {
@@ -50,12 +118,142 @@ func (bu *blockUseCase) GetByNumber(ctx context.Context, blockNum uint64) (*doma
 		return nil, err
 	}
 
+	return bu.withTransactions(ctx, block)
+}
+
+func (bu *blockUseCase) GetByHash(ctx context.Context, blockHash string) (*domain.Block, error) {
+	block, err := bu.repo.GetByHash(ctx, blockHash)
+	if err == gorm.ErrRecordNotFound {
+		return nil, domain.ErrBlockNotExist
+	}
+
+	if err != nil {
+		log.Err(err).Msg("get block by block_hash fail")
+		return nil, err
+	}
+
+	return bu.withTransactions(ctx, block)
+}
+
+// GetByTag resolve block tag to stored block .
+// safe and finalized fall back to latest stable block when node never reported them.
+func (bu *blockUseCase) GetByTag(ctx context.Context, tag string) (*domain.Block, error) {
+	var block *domain.BlockDb
+	var err error
+	switch tag {
+	case domain.BlockTagLatest:
+		blocks, err := bu.repo.List(ctx, domain.NewLatestBlockFilter())
+		if err != nil {
+			log.Err(err).Msg("get latest block fail")
+			return nil, err
+		}
+		if len(blocks) == 0 {
+			return nil, domain.ErrBlockNotExist
+		}
+		block = &blocks[0]
+	case domain.BlockTagLatestStable:
+		block, err = bu.repo.GetLatestStable(ctx)
+	case domain.BlockTagSafe, domain.BlockTagFinalized:
+		checkpoint, err := bu.checkpointUcase.Get(ctx, tag)
+		if err == domain.ErrCheckpointNotExist {
+			return bu.GetByTag(ctx, domain.BlockTagLatestStable)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return bu.GetByNumber(ctx, checkpoint.BlockNum)
+	default:
+		return nil, domain.ErrInvalidBlockID
+	}
+
+	if err == gorm.ErrRecordNotFound {
+		return nil, domain.ErrBlockNotExist
+	}
+
+	if err != nil {
+		log.Err(err).Str("tag", tag).Msg("get block by tag fail")
+		return nil, err
+	}
+
+	return bu.withTransactions(ctx, block)
+}
+
+// GetByID resolve block hash , hex or decimal number , or tag to stored block with its safe and finalized status
+func (bu *blockUseCase) GetByID(ctx context.Context, id string) (*domain.Block, error) {
+	var block *domain.Block
+	var err error
+	switch {
+	case strings.HasPrefix(id, "0x") && len(id) == 2+2*common.HashLength:
+		if _, err = hexutil.Decode(id); err != nil {
+			return nil, domain.ErrInvalidBlockID
+		}
+		block, err = bu.GetByHash(ctx, common.HexToHash(id).String())
+	case strings.HasPrefix(id, "0x"):
+		blockNum, parseErr := hexutil.DecodeUint64(id)
+		if parseErr != nil {
+			return nil, domain.ErrInvalidBlockID
+		}
+		block, err = bu.GetByNumber(ctx, blockNum)
+	default:
+		if blockNum, parseErr := strconv.ParseUint(id, 10, 64); parseErr == nil {
+			block, err = bu.GetByNumber(ctx, blockNum)
+		} else {
+			block, err = bu.GetByTag(ctx, id)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = bu.setFinality(ctx, block); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// setFinality mark whether block is not above node safe and finalized block
+func (bu *blockUseCase) setFinality(ctx context.Context, block *domain.Block) error {
+	flags := map[string]*bool{
+		domain.CheckpointSafe:      &block.Safe,
+		domain.CheckpointFinalized: &block.Finalized,
+	}
+
+	for name, flag := range flags {
+		checkpoint, err := bu.checkpointUcase.Get(ctx, name)
+		if err == domain.ErrCheckpointNotExist {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		*flag = block.BlockNum <= checkpoint.BlockNum
+	}
+
+	return nil
+}
+
+// withTransactions attach transaction hashes , uncles and withdrawals to block
+func (bu *blockUseCase) withTransactions(ctx context.Context, block *domain.BlockDb) (*domain.Block, error) {
 	txs, err := bu.transactionUcase.GetTxHashesByBlockHash(ctx, block.BlockHash)
 	if err != nil {
 		log.Err(err).Msg("get tx hashes by block_hash fail")
 		return nil, err
 	}
 
+	block.Uncles, err = bu.repo.ListUncles(ctx, block.BlockHash)
+	if err != nil {
+		log.Err(err).Msg("list uncles by block_hash fail")
+		return nil, err
+	}
+
+	block.Withdrawals, err = bu.repo.ListWithdrawals(ctx, block.BlockHash)
+	if err != nil {
+		log.Err(err).Msg("list withdrawals by block_hash fail")
+		return nil, err
+	}
+
 	return &domain.Block{
 		BlockDb:           *block,
 		TransactionHashes: txs,
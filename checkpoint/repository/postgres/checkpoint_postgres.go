@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+	"github.com/ryanCool/ethService/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresCheckpointRepository struct {
+	Db *gorm.DB
+}
+
+// NewPostgresCheckpointRepository will create an object that represent the checkpoint.Repository interface
+func NewPostgresCheckpointRepository(db *gorm.DB) domain.CheckpointRepository {
+	return &postgresCheckpointRepository{db}
+}
+
+func (p *postgresCheckpointRepository) Get(ctx context.Context, name string) (*domain.SyncCheckpoint, error) {
+	var res *domain.SyncCheckpoint
+	if err := p.Db.Table("eth.sync_checkpoints").Where("name = ?", name).First(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Save insert checkpoint or move the existing one to new block
+func (p *postgresCheckpointRepository) Save(ctx context.Context, checkpoint *domain.SyncCheckpoint) error {
+	return p.Db.Table("eth.sync_checkpoints").Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "name"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"block_num":  checkpoint.BlockNum,
+			"block_hash": checkpoint.BlockHash,
+			"updated_at": gorm.Expr("date_part('epoch'::text, now()) * (1000)::double precision"),
+		}),
+	}).Create(&checkpoint).Error
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"github.com/ryanCool/ethService/domain"
+	"gorm.io/gorm"
+	"time"
+)
+
+type checkpointUseCase struct {
+	repo           domain.CheckpointRepository
+	contextTimeout time.Duration
+}
+
+func NewCheckpointUseCase(a domain.CheckpointRepository, timeout time.Duration) domain.CheckpointUseCase {
+	return &checkpointUseCase{
+		repo:           a,
+		contextTimeout: timeout,
+	}
+}
+
+func (cu *checkpointUseCase) Get(ctx context.Context, name string) (*domain.SyncCheckpoint, error) {
+	checkpoint, err := cu.repo.Get(ctx, name)
+	if err == gorm.ErrRecordNotFound {
+		return nil, domain.ErrCheckpointNotExist
+	}
+
+	if err != nil {
+		log.Err(err).Msg("get checkpoint by name fail")
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+func (cu *checkpointUseCase) Save(ctx context.Context, checkpoint *domain.SyncCheckpoint) error {
+	return cu.repo.Save(ctx, checkpoint)
+}
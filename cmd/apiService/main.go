@@ -13,14 +13,35 @@ import (
 	"syscall"
 	"time"
 
+	abiHttp "github.com/ryanCool/ethService/abi/delivery/http"
+	abiRepo "github.com/ryanCool/ethService/abi/repository/postgres"
+	abiUcase "github.com/ryanCool/ethService/abi/usecase"
+	balanceHttp "github.com/ryanCool/ethService/balance/delivery/http"
+	balanceRepo "github.com/ryanCool/ethService/balance/repository/postgres"
+	balanceUcase "github.com/ryanCool/ethService/balance/usecase"
 	blockHttp "github.com/ryanCool/ethService/block/delivery/http"
 	blockRepo "github.com/ryanCool/ethService/block/repository/postgres"
 	blockUcase "github.com/ryanCool/ethService/block/usecase"
+	checkpointRepo "github.com/ryanCool/ethService/checkpoint/repository/postgres"
+	checkpointUcase "github.com/ryanCool/ethService/checkpoint/usecase"
 	"github.com/ryanCool/ethService/config"
+	contractHttp "github.com/ryanCool/ethService/contract/delivery/http"
+	contractRepo "github.com/ryanCool/ethService/contract/repository/postgres"
+	contractUcase "github.com/ryanCool/ethService/contract/usecase"
 	"github.com/ryanCool/ethService/database"
+	jsonRpcHttp "github.com/ryanCool/ethService/jsonrpc/delivery/http"
+	signatureHttp "github.com/ryanCool/ethService/signature/delivery/http"
+	signatureRepo "github.com/ryanCool/ethService/signature/repository/postgres"
+	signatureUcase "github.com/ryanCool/ethService/signature/usecase"
+	tokenHttp "github.com/ryanCool/ethService/token/delivery/http"
+	tokenRepo "github.com/ryanCool/ethService/token/repository/postgres"
+	tokenUcase "github.com/ryanCool/ethService/token/usecase"
 	transactionHttp "github.com/ryanCool/ethService/transaction/delivery/http"
 	transactionRepo "github.com/ryanCool/ethService/transaction/repository/postgres"
 	transactionUcase "github.com/ryanCool/ethService/transaction/usecase"
+	watchlistHttp "github.com/ryanCool/ethService/watchlist/delivery/http"
+	watchlistRepo "github.com/ryanCool/ethService/watchlist/repository/postgres"
+	watchlistUcase "github.com/ryanCool/ethService/watchlist/usecase"
 )
 
 func main() {
@@ -38,16 +59,59 @@ func main() {
 
 	db := database.GetDB()
 
+	adminToken := config.GetString("ADMIN_API_TOKEN")
+
+	//init signature service
+	sp := signatureRepo.NewPostgresSignatureRepository(db)
+	su := signatureUcase.NewSignatureUseCase(sp, timeoutContext)
+	signatureHttp.NewSignatureHandler(engine, su, adminToken)
+
 	//init transaction service
 	tp := transactionRepo.NewPostgresTransactionRepository(db)
-	tu := transactionUcase.NewTransactionUseCase(tp, timeoutContext)
+	tu := transactionUcase.NewTransactionUseCase(tp, su, timeoutContext)
 	transactionHttp.NewTransactionHandler(engine, tu)
 
+	//init checkpoint service
+	cp := checkpointRepo.NewPostgresCheckpointRepository(db)
+	cu := checkpointUcase.NewCheckpointUseCase(cp, timeoutContext)
+
 	//init block service
 	bp := blockRepo.NewPostgresBlockRepository(db)
-	bu := blockUcase.NewBlockUseCase(bp, tu, timeoutContext)
+	bu := blockUcase.NewBlockUseCase(bp, tu, cu, timeoutContext)
 	blockHttp.NewBlockHandler(engine, bu)
 
+	//init token service
+	tkp := tokenRepo.NewPostgresTokenRepository(db)
+	tku := tokenUcase.NewTokenUseCase(tkp, timeoutContext)
+	tokenHttp.NewTokenHandler(engine, tku)
+
+	//init balance service
+	blp := balanceRepo.NewPostgresBalanceRepository(db)
+	blu := balanceUcase.NewBalanceUseCase(blp, timeoutContext)
+	balanceHttp.NewBalanceHandler(engine, blu, cu)
+
+	//init watchlist service
+	wp := watchlistRepo.NewPostgresWatchlistRepository(db)
+	wu := watchlistUcase.NewWatchlistUseCase(wp, timeoutContext)
+	watchlistHttp.NewWatchlistHandler(engine, wu, adminToken)
+
+	//init abi registry
+	ap := abiRepo.NewPostgresAbiRepository(db)
+	au := abiUcase.NewAbiUseCase(ap, timeoutContext)
+	abiHttp.NewAbiHandler(engine, au, adminToken)
+
+	//init contract registry
+	ctp := contractRepo.NewPostgresContractRepository(db)
+	ctu := contractUcase.NewContractUseCase(ctp, timeoutContext)
+	contractHttp.NewContractHandler(engine, ctu)
+
+	//init log filter
+	maxBlockRange := config.GetUint64("LOG_FILTER_MAX_BLOCK_RANGE")
+	transactionHttp.NewLogHandler(engine, tu, bu, maxBlockRange)
+
+	//init json-rpc facade
+	jsonRpcHttp.NewJsonRpcHandler(engine, bu, tu, maxBlockRange)
+
 	//create http server to serve rest api
 	serverAddress := fmt.Sprintf("%s:%s", config.GetString("SERVER_HOST"), config.GetString("SERVER_PORT"))
 	server := &http.Server{
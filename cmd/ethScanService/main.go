@@ -2,16 +2,32 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	abiRepo "github.com/ryanCool/ethService/abi/repository/postgres"
+	abiUcase "github.com/ryanCool/ethService/abi/usecase"
+	balanceRepo "github.com/ryanCool/ethService/balance/repository/postgres"
+	balanceUcase "github.com/ryanCool/ethService/balance/usecase"
 	blockRepo "github.com/ryanCool/ethService/block/repository/postgres"
 	blockUcase "github.com/ryanCool/ethService/block/usecase"
+	checkpointRepo "github.com/ryanCool/ethService/checkpoint/repository/postgres"
+	checkpointUcase "github.com/ryanCool/ethService/checkpoint/usecase"
 	"github.com/ryanCool/ethService/config"
+	contractRepo "github.com/ryanCool/ethService/contract/repository/postgres"
+	contractUcase "github.com/ryanCool/ethService/contract/usecase"
 	"github.com/ryanCool/ethService/database"
 	"github.com/ryanCool/ethService/eth"
 	"github.com/ryanCool/ethService/ethclient"
+	signatureRepo "github.com/ryanCool/ethService/signature/repository/postgres"
+	signatureUcase "github.com/ryanCool/ethService/signature/usecase"
+	tokenRepo "github.com/ryanCool/ethService/token/repository/postgres"
+	tokenUcase "github.com/ryanCool/ethService/token/usecase"
 	transactionRepo "github.com/ryanCool/ethService/transaction/repository/postgres"
 	transactionUcase "github.com/ryanCool/ethService/transaction/usecase"
+	watchlistRepo "github.com/ryanCool/ethService/watchlist/repository/postgres"
+	watchlistUcase "github.com/ryanCool/ethService/watchlist/usecase"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -29,20 +45,55 @@ func main() {
 	ethclient.Initialize()
 	defer ethclient.Finalize()
 
+	//serve rpc pool stats at /debug/vars
+	go func() {
+		if err := http.ListenAndServe(fmt.Sprintf(":%s", config.GetString("METRICS_PORT")), nil); err != nil {
+			log.Err(err).Msg("metrics server fail")
+		}
+	}()
+
 	database.Initialize(ctx)
 	defer database.Finalize(ctx)
 
 	db := database.GetDB()
 
+	//init signature service
+	sp := signatureRepo.NewPostgresSignatureRepository(db)
+	su := signatureUcase.NewSignatureUseCase(sp, timeoutContext)
+
 	//init transaction service
 	tp := transactionRepo.NewPostgresTransactionRepository(db)
-	tu := transactionUcase.NewTransactionUseCase(tp, timeoutContext)
+	tu := transactionUcase.NewTransactionUseCase(tp, su, timeoutContext)
+
+	//init checkpoint service
+	cp := checkpointRepo.NewPostgresCheckpointRepository(db)
+	cu := checkpointUcase.NewCheckpointUseCase(cp, timeoutContext)
+
+	//init token service
+	tkp := tokenRepo.NewPostgresTokenRepository(db)
+	tku := tokenUcase.NewTokenUseCase(tkp, timeoutContext)
+
+	//init balance service
+	blp := balanceRepo.NewPostgresBalanceRepository(db)
+	blu := balanceUcase.NewBalanceUseCase(blp, timeoutContext)
+
+	//init watchlist service
+	wp := watchlistRepo.NewPostgresWatchlistRepository(db)
+	wu := watchlistUcase.NewWatchlistUseCase(wp, timeoutContext)
+
+	//init abi service
+	ap := abiRepo.NewPostgresAbiRepository(db)
+	au := abiUcase.NewAbiUseCase(ap, timeoutContext)
+
+	//init contract service
+	ctp := contractRepo.NewPostgresContractRepository(db)
+	ctu := contractUcase.NewContractUseCase(ctp, timeoutContext)
 
 	//init block service
 	bp := blockRepo.NewPostgresBlockRepository(db)
-	bu := blockUcase.NewBlockUseCase(bp, tu, timeoutContext)
+	bu := blockUcase.NewBlockUseCase(bp, tu, cu, timeoutContext)
 
-	ethScan := eth.NewEthScan(ethclient.RpcClient, ethclient.WsClient, tu, bu)
+	ethScan := eth.NewEthScan(ethclient.RpcClient, ethclient.RpcRawClient, ethclient.DialWs, tu, bu, cu, tku, blu, wu, au, ctu)
 	ethScan.Initialize(ctx)
 
 	quit := make(chan os.Signal, 1)
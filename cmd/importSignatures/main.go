@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/ryanCool/ethService/config"
+	"github.com/ryanCool/ethService/database"
+	"github.com/ryanCool/ethService/domain"
+	"github.com/ryanCool/ethService/signature"
+	signatureRepo "github.com/ryanCool/ethService/signature/repository/postgres"
+	signatureUcase "github.com/ryanCool/ethService/signature/usecase"
+	"os"
+	"time"
+)
+
+// importBatchSize is the number of signatures added per call
+const importBatchSize = 1000
+
+func main() {
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+
+	file := flag.String("file", "", "signature dump to import , bundled table if empty")
+	format := flag.String("format", "text", "dump format : text , json (4byte.directory) or dir (ethereum-lists/4bytes)")
+	kind := flag.String("kind", "", "kind of bare text signatures in text dump : function or event")
+	flag.Parse()
+
+	signatures, err := parseDump(*file, *format, *kind)
+	if err != nil {
+		log.Fatal().Err(err).Msg("parse signature dump fail")
+	}
+
+	ctx := context.Background()
+	timeoutContext := time.Duration(config.GetInt("CONTEXT_TIMEOUT_SECS")) * time.Second
+
+	database.Initialize(ctx)
+	defer database.Finalize(ctx)
+
+	//init signature service
+	sp := signatureRepo.NewPostgresSignatureRepository(database.GetDB())
+	su := signatureUcase.NewSignatureUseCase(sp, timeoutContext)
+
+	byKind := map[string][]domain.Signature{}
+	for _, s := range signatures {
+		byKind[s.Kind] = append(byKind[s.Kind], s)
+	}
+
+	var imported, skipped int
+	for k, list := range byKind {
+		for start := 0; start < len(list); start += importBatchSize {
+			end := start + importBatchSize
+			if end > len(list) {
+				end = len(list)
+			}
+
+			n, err := importBatch(ctx, su, k, list[start:end])
+			if err != nil {
+				log.Fatal().Err(err).Msg("import signatures fail")
+			}
+			imported += n
+			skipped += end - start - n
+		}
+	}
+
+	log.Info().Int("imported", imported).Int("skipped", skipped).Msg("import signatures done")
+}
+
+func parseDump(file, format, kind string) ([]domain.Signature, error) {
+	if file == "" {
+		return signature.ParseText(bytes.NewReader(signature.Bundled), kind)
+	}
+
+	if format == "dir" {
+		return signature.ParseDir(file)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if format == "json" {
+		return signature.ParseJSON(f)
+	}
+	return signature.ParseText(f, kind)
+}
+
+// importBatch add signatures of one kind , when batch has malformed or mismatched entries
+// they are added one by one so only bad entries are skipped
+func importBatch(ctx context.Context, su domain.SignatureUseCase, kind string, batch []domain.Signature) (int, error) {
+	texts := make([]string, 0, len(batch))
+	for _, s := range batch {
+		texts = append(texts, s.TextSignature)
+	}
+
+	added, err := su.Add(ctx, kind, texts)
+	if err == nil && !hashMismatch(batch, added) {
+		return len(added), nil
+	}
+
+	if err != nil && err != domain.ErrInvalidSignature {
+		return 0, err
+	}
+
+	var n int
+	for _, s := range batch {
+		added, err := su.Add(ctx, kind, []string{s.TextSignature})
+		if err == domain.ErrInvalidSignature {
+			log.Warn().Str("signature", s.TextSignature).Msg("skip malformed signature")
+			continue
+		}
+
+		if err != nil {
+			return n, err
+		}
+
+		if hashMismatch([]domain.Signature{s}, added) {
+			log.Warn().Str("hash", s.Hash).Str("signature", s.TextSignature).Msg("hash does not match signature , saved under computed hash")
+		}
+		n++
+	}
+	return n, nil
+}
+
+// hashMismatch reports whether dump hash of any entry differs from the computed one , entries without hash always match
+func hashMismatch(batch []domain.Signature, added []domain.Signature) bool {
+	for i, s := range batch {
+		if s.Hash != "" && s.Hash != added[i].Hash {
+			return true
+		}
+	}
+	return false
+}
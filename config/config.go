@@ -16,6 +16,11 @@ func GetString(key string) string {
 	return val
 }
 
+// LookupString returns a setting in string , and whether it is set.
+func LookupString(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
 // GetBool returns a setting in bool.
 func GetBool(key string) bool {
 	var val bool
@@ -73,3 +78,14 @@ func GetUint64(key string) uint64 {
 
 	return val
 }
+
+// GetFloat64 returns a setting in 64-bit float.
+func GetFloat64(key string) float64 {
+	var val float64
+	var err error
+	if val, err = strconv.ParseFloat(GetString(key), 64); err != nil {
+		panic(err)
+	}
+
+	return val
+}
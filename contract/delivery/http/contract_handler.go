@@ -0,0 +1,124 @@
+package http
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gin-gonic/gin"
+	"github.com/ryanCool/ethService/domain"
+	"github.com/ryanCool/ethService/helper"
+	"net/http"
+	"strconv"
+)
+
+// ContractHandler represent the httphandler for contract registry
+type ContractHandler struct {
+	CUseCase domain.ContractUseCase
+}
+
+func NewContractHandler(e *gin.Engine, cu domain.ContractUseCase) {
+	handler := &ContractHandler{
+		CUseCase: cu,
+	}
+
+	cg := e.Group("contracts")
+
+	cg.GET("/", handler.ListContracts)
+	cg.GET("/:addr", handler.GetContract)
+
+	ag := e.Group("address")
+
+	ag.GET("/:addr/contracts", handler.ListCreatedContracts)
+}
+
+func (a *ContractHandler) GetContract(ctx *gin.Context) {
+	address := ctx.Param("addr")
+	if !common.IsHexAddress(address) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	contract, err := a.CUseCase.Get(ctx, common.HexToAddress(address).String())
+	if err == domain.ErrContractNotExist {
+		helper.RespondWithError(ctx, http.StatusNotFound, err)
+		return
+	}
+
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, contract)
+}
+
+// ListContracts list contracts , optionally only clones sharing code_hash
+func (a *ContractHandler) ListContracts(ctx *gin.Context) {
+	filter := &domain.ContractFilter{}
+	if codeHash := ctx.Query("code_hash"); codeHash != "" {
+		b, err := hexutil.Decode(codeHash)
+		if err != nil || len(b) != common.HashLength {
+			helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidCodeHash)
+			return
+		}
+		filter.CodeHash = common.BytesToHash(b).String()
+	}
+
+	a.listContracts(ctx, filter)
+}
+
+// ListCreatedContracts list contracts created by an address
+func (a *ContractHandler) ListCreatedContracts(ctx *gin.Context) {
+	address := ctx.Param("addr")
+	if !common.IsHexAddress(address) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	a.listContracts(ctx, &domain.ContractFilter{Creator: common.HexToAddress(address).String()})
+}
+
+// listContracts respond one page of contracts matching filter
+func (a *ContractHandler) listContracts(ctx *gin.Context, filter *domain.ContractFilter) {
+	switch ctx.DefaultQuery("order", "desc") {
+	case "desc":
+		filter.Desc = true
+	case "asc":
+	default:
+		ctx.JSON(http.StatusBadRequest, "order should be asc or desc")
+		return
+	}
+
+	filter.Limit, _ = strconv.Atoi(ctx.Query("limit"))
+	if filter.Limit == 0 {
+		//set default to 20
+		filter.Limit = 20
+	}
+
+	if filter.Limit < 0 || filter.Limit > 100 {
+		ctx.JSON(http.StatusBadRequest, "limit should be 0~100")
+		return
+	}
+
+	if cursor := ctx.Query("cursor"); cursor != "" {
+		position, err := helper.DecodeCursor(cursor, 3)
+		if err != nil {
+			helper.RespondWithError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		filter.After = &domain.InternalTxPosition{BlockNum: position[0], TxIndex: uint(position[1]), TraceIndex: int(position[2])}
+	}
+
+	contracts, err := a.CUseCase.List(ctx, filter)
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := map[string]interface{}{"contracts": contracts}
+	if len(contracts) == filter.Limit {
+		last := contracts[len(contracts)-1]
+		res["next"] = helper.EncodeCursor(last.BlockNum, uint64(last.TxIndex), uint64(last.TraceIndex))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"github.com/ryanCool/ethService/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresContractRepository struct {
+	Db *gorm.DB
+}
+
+// NewPostgresContractRepository will create an object that represent the contract.Repository interface
+func NewPostgresContractRepository(db *gorm.DB) domain.ContractRepository {
+	return &postgresContractRepository{db}
+}
+
+// Save insert contracts , creations saved by an earlier attempt are skipped
+func (p *postgresContractRepository) Save(ctx context.Context, contracts []domain.Contract) error {
+	return p.Db.Table("eth.contracts").Clauses(clause.OnConflict{DoNothing: true}).Create(&contracts).Error
+}
+
+// Get returns the latest creation of address , CREATE2 address can be created again after self destruct
+func (p *postgresContractRepository) Get(ctx context.Context, address string) (*domain.Contract, error) {
+	var res *domain.Contract
+	if err := p.Db.Table("eth.contracts").Where("address = ?", address).Order("block_num desc, tx_index desc, trace_index desc").First(&res).Error; err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// List list contracts matching filter ordered by position in chain
+func (p *postgresContractRepository) List(ctx context.Context, filter *domain.ContractFilter) ([]domain.Contract, error) {
+	q := p.Db.Table("eth.contracts")
+	if filter.Creator != "" {
+		q = q.Where("creator = ?", filter.Creator)
+	}
+	if filter.CodeHash != "" {
+		q = q.Where("code_hash = ?", filter.CodeHash)
+	}
+
+	order := "block_num, tx_index, trace_index"
+	if filter.Desc {
+		order = "block_num desc, tx_index desc, trace_index desc"
+	}
+
+	if filter.After != nil {
+		if filter.Desc {
+			q = q.Where("(block_num, tx_index, trace_index) < (?, ?, ?)", filter.After.BlockNum, filter.After.TxIndex, filter.After.TraceIndex)
+		} else {
+			q = q.Where("(block_num, tx_index, trace_index) > (?, ?, ?)", filter.After.BlockNum, filter.After.TxIndex, filter.After.TraceIndex)
+		}
+	}
+
+	var res []domain.Contract
+	if err := q.Order(order).Limit(filter.Limit).Find(&res).Error; err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"github.com/ryanCool/ethService/domain"
+	"gorm.io/gorm"
+	"time"
+)
+
+type contractUseCase struct {
+	repo           domain.ContractRepository
+	contextTimeout time.Duration
+}
+
+func NewContractUseCase(a domain.ContractRepository, timeout time.Duration) domain.ContractUseCase {
+	return &contractUseCase{
+		repo:           a,
+		contextTimeout: timeout,
+	}
+}
+
+func (cu *contractUseCase) Save(ctx context.Context, contracts []domain.Contract) error {
+	if len(contracts) == 0 {
+		return nil
+	}
+	return cu.repo.Save(ctx, contracts)
+}
+
+func (cu *contractUseCase) Get(ctx context.Context, address string) (*domain.Contract, error) {
+	contract, err := cu.repo.Get(ctx, address)
+	if err == gorm.ErrRecordNotFound {
+		return nil, domain.ErrContractNotExist
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return contract, nil
+}
+
+func (cu *contractUseCase) List(ctx context.Context, filter *domain.ContractFilter) ([]domain.Contract, error) {
+	return cu.repo.List(ctx, filter)
+}
@@ -13,46 +13,476 @@ CREATE TABLE IF NOT EXISTS eth.blocks
     block_hash   VARCHAR(255) UNIQUE NOT NULL,
     block_time   BIGINT,
     parent_hash VARCHAR(255),
-    
+    tx_count   BIGINT NOT NULL DEFAULT 0,
+    miner      VARCHAR(255),
+    gas_limit  BIGINT,
+    gas_used   BIGINT,
+    base_fee_per_gas  VARCHAR(255),
+    difficulty        VARCHAR(255),
+    total_difficulty  VARCHAR(255),
+    extra_data        bytea,
+    size              BIGINT,
+    state_root        VARCHAR(255),
+    transactions_root VARCHAR(255),
+    receipts_root     VARCHAR(255),
+    withdrawals_root  VARCHAR(255),
+    sha3_uncles       VARCHAR(255),
+    nonce             VARCHAR(255),
+    mix_hash          VARCHAR(255),
+    logs_bloom        bytea,
+
     stable BOOL,
     created_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision),
     updated_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision)
 );
 
+-- Upgrade: tx_count added after first release , it is backfilled once transactions table exists below
+ALTER TABLE eth.blocks ADD COLUMN IF NOT EXISTS tx_count BIGINT;
+
+-- Upgrade: header fields added after first release
+ALTER TABLE eth.blocks
+    ADD COLUMN IF NOT EXISTS miner             VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS gas_limit         BIGINT,
+    ADD COLUMN IF NOT EXISTS gas_used          BIGINT,
+    ADD COLUMN IF NOT EXISTS base_fee_per_gas  VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS difficulty        VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS total_difficulty  VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS extra_data        bytea,
+    ADD COLUMN IF NOT EXISTS size              BIGINT,
+    ADD COLUMN IF NOT EXISTS state_root        VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS transactions_root VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS receipts_root     VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS withdrawals_root  VARCHAR(255);
+
+-- Upgrade: sha3_uncles added after first release
+ALTER TABLE eth.blocks ADD COLUMN IF NOT EXISTS sha3_uncles VARCHAR(255);
+
+-- Upgrade: nonce , mix_hash and logs_bloom added after first release
+ALTER TABLE eth.blocks
+    ADD COLUMN IF NOT EXISTS nonce      VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS mix_hash   VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS logs_bloom bytea;
+
+CREATE INDEX IF NOT EXISTS blocks_block_time_idx ON eth.blocks (block_time);
+
+-- Table: eth.uncles
+CREATE TABLE IF NOT EXISTS eth.uncles
+(
+    block_hash  VARCHAR(255) NOT NULL REFERENCES eth.blocks (block_hash) ON DELETE CASCADE,
+    block_num   BIGINT NOT NULL,
+    uncle_index BIGINT NOT NULL,
+    uncle_hash  VARCHAR(255) NOT NULL,
+    uncle_num   BIGINT NOT NULL,
+    miner       VARCHAR(255) NOT NULL,
+    header      JSONB,
+
+    PRIMARY KEY (block_hash, uncle_index)
+);
+
+-- Upgrade: header added after first release
+ALTER TABLE eth.uncles ADD COLUMN IF NOT EXISTS header JSONB;
+
+CREATE INDEX IF NOT EXISTS uncles_miner_idx ON eth.uncles (miner, block_num);
+
+-- Table: eth.withdrawals
+CREATE TABLE IF NOT EXISTS eth.withdrawals
+(
+    block_hash      VARCHAR(255) NOT NULL REFERENCES eth.blocks (block_hash) ON DELETE CASCADE,
+    block_num       BIGINT NOT NULL,
+    withdrawal_index  BIGINT NOT NULL,
+    validator_index BIGINT NOT NULL,
+    address         VARCHAR(255) NOT NULL,
+    amount          BIGINT NOT NULL,
+
+    PRIMARY KEY (block_hash, withdrawal_index)
+);
+
+CREATE INDEX IF NOT EXISTS withdrawals_address_idx ON eth.withdrawals (address, block_num);
+
 -- Table: eth.transactions
 CREATE TABLE IF NOT EXISTS eth.transactions
 (
     block_hash VARCHAR(255) REFERENCES eth.blocks (block_hash) ON DELETE CASCADE,
+    block_num  BIGINT,
+    tx_index   BIGINT,
     tx_hash    VARCHAR(255) UNIQUE NOT NULL,
+    tx_type    SMALLINT,
     tx_from    VARCHAR(255),
     tx_to      VARCHAR(255),
     nonce     BIGINT,
     tx_data    bytea,
     tx_value   VARCHAR(255),
+    chain_id   VARCHAR(255),
+    gas        BIGINT,
+    gas_price  VARCHAR(255),
+    max_fee_per_gas          VARCHAR(255),
+    max_priority_fee_per_gas VARCHAR(255),
+    v          VARCHAR(255),
+    r          VARCHAR(255),
+    s          VARCHAR(255),
+    decoded_input JSONB,
 
     created_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision),
     updated_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision)
 );
 
+-- Upgrade: block position added after first release , block_num is backfilled from block
+ALTER TABLE eth.transactions
+    ADD COLUMN IF NOT EXISTS block_num BIGINT,
+    ADD COLUMN IF NOT EXISTS tx_index  BIGINT;
+UPDATE eth.transactions t SET block_num = b.block_num FROM eth.blocks b
+WHERE t.block_hash = b.block_hash AND t.block_num IS NULL;
+
+-- Upgrade: typed transaction fields added after first release
+ALTER TABLE eth.transactions
+    ADD COLUMN IF NOT EXISTS tx_type   SMALLINT,
+    ADD COLUMN IF NOT EXISTS chain_id  VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS gas       BIGINT,
+    ADD COLUMN IF NOT EXISTS gas_price VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS max_fee_per_gas          VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS max_priority_fee_per_gas VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS v         VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS r         VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS s         VARCHAR(255);
+
+-- Upgrade: decoded_input added after first release
+ALTER TABLE eth.transactions ADD COLUMN IF NOT EXISTS decoded_input JSONB;
+
+-- tx_count is backfilled from stored transactions before it gets its default , otherwise gap auditor would refetch every old block
+UPDATE eth.blocks b SET tx_count = (SELECT count(*) FROM eth.transactions t WHERE t.block_hash = b.block_hash)
+WHERE b.tx_count IS NULL;
+ALTER TABLE eth.blocks ALTER COLUMN tx_count SET DEFAULT 0, ALTER COLUMN tx_count SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS transactions_tx_from_idx ON eth.transactions (tx_from, block_num, tx_index);
+CREATE INDEX IF NOT EXISTS transactions_tx_to_idx ON eth.transactions (tx_to, block_num, tx_index);
+
+-- Table: eth.access_lists
+CREATE TABLE IF NOT EXISTS eth.access_lists
+(
+    tx_hash      VARCHAR(255) NOT NULL REFERENCES eth.transactions (tx_hash) ON DELETE CASCADE,
+    entry_index  BIGINT NOT NULL,
+    address      VARCHAR(255) NOT NULL,
+    storage_keys JSONB,
+
+    PRIMARY KEY (tx_hash, entry_index)
+);
+
+-- Table: eth.internal_transactions
+CREATE TABLE IF NOT EXISTS eth.internal_transactions
+(
+    tx_hash       VARCHAR(255) NOT NULL REFERENCES eth.transactions (tx_hash) ON DELETE CASCADE,
+    block_hash    VARCHAR(255),
+    block_num     BIGINT,
+    tx_index      BIGINT,
+    trace_index   BIGINT NOT NULL,
+    trace_address JSONB,
+    depth         BIGINT,
+    call_type     VARCHAR(255),
+    from_address  VARCHAR(255),
+    to_address    VARCHAR(255),
+    value         NUMERIC(78, 0),
+    gas           BIGINT,
+    gas_used      BIGINT,
+    input         bytea,
+    output        bytea,
+    error         TEXT,
+
+    PRIMARY KEY (tx_hash, trace_index)
+);
+
+CREATE INDEX IF NOT EXISTS internal_transactions_from_idx ON eth.internal_transactions (from_address, block_num, tx_index, trace_index);
+CREATE INDEX IF NOT EXISTS internal_transactions_to_idx ON eth.internal_transactions (to_address, block_num, tx_index, trace_index);
+
+-- Table: eth.contracts
+CREATE TABLE IF NOT EXISTS eth.contracts
+(
+    address     VARCHAR(255) NOT NULL,
+    creator     VARCHAR(255) NOT NULL,
+    tx_hash     VARCHAR(255) NOT NULL REFERENCES eth.transactions (tx_hash) ON DELETE CASCADE,
+    block_hash  VARCHAR(255),
+    block_num   BIGINT,
+    tx_index    BIGINT,
+    trace_index BIGINT NOT NULL,
+    bytecode    bytea,
+    code_hash   VARCHAR(255),
+    code_at_latest BOOL NOT NULL DEFAULT false,
+
+    created_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision),
+    PRIMARY KEY (tx_hash, trace_index)
+);
+
+-- Upgrade: code_at_latest added after first release
+ALTER TABLE eth.contracts ADD COLUMN IF NOT EXISTS code_at_latest BOOL NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS contracts_address_idx ON eth.contracts (address);
+CREATE INDEX IF NOT EXISTS contracts_creator_idx ON eth.contracts (creator, block_num, tx_index, trace_index);
+CREATE INDEX IF NOT EXISTS contracts_code_hash_idx ON eth.contracts (code_hash, block_num, tx_index, trace_index);
+
 -- Table: eth.receipts
 CREATE TABLE IF NOT EXISTS eth.receipts
 (
     tx_hash   VARCHAR(255)  UNIQUE NOT NULL REFERENCES eth.transactions (tx_hash) ON DELETE CASCADE,
+    tx_type   SMALLINT,
+    status    BIGINT,
+    gas_used  BIGINT,
+    cumulative_gas_used BIGINT,
+    effective_gas_price VARCHAR(255),
+    contract_address    VARCHAR(255),
+    logs_bloom bytea,
 
     created_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision),
     updated_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision)
 );
 
+-- Upgrade: receipt fields added after first release , CREATE TABLE IF NOT EXISTS skips existing tables
+ALTER TABLE eth.receipts
+    ADD COLUMN IF NOT EXISTS tx_type             SMALLINT,
+    ADD COLUMN IF NOT EXISTS status              BIGINT,
+    ADD COLUMN IF NOT EXISTS gas_used            BIGINT,
+    ADD COLUMN IF NOT EXISTS cumulative_gas_used BIGINT,
+    ADD COLUMN IF NOT EXISTS effective_gas_price VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS contract_address    VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS logs_bloom          bytea;
+
+CREATE INDEX IF NOT EXISTS receipts_status_idx ON eth.receipts (status);
+
 -- Table: eth.transaction_logs
 CREATE TABLE IF NOT EXISTS eth.transaction_logs
 (
     tx_hash   VARCHAR(255) NOT NULL REFERENCES eth.receipts (tx_hash) ON DELETE CASCADE,
+    block_hash VARCHAR(255),
+    block_num BIGINT,
+    tx_index  BIGINT,
     log_index BIGINT,
-    log_data   bytea
+    address   VARCHAR(255),
+    topic0    VARCHAR(255),
+    topic1    VARCHAR(255),
+    topic2    VARCHAR(255),
+    topic3    VARCHAR(255),
+    log_data   bytea,
+    decoded    JSONB
+);
+
+-- Upgrade: address , topics and position added after first release , block_num is backfilled from block of transaction
+ALTER TABLE eth.transaction_logs
+    ADD COLUMN IF NOT EXISTS block_num BIGINT,
+    ADD COLUMN IF NOT EXISTS tx_index  BIGINT,
+    ADD COLUMN IF NOT EXISTS address   VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS topic0    VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS topic1    VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS topic2    VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS topic3    VARCHAR(255);
+UPDATE eth.transaction_logs l SET block_num = b.block_num FROM eth.transactions t, eth.blocks b
+WHERE l.tx_hash = t.tx_hash AND t.block_hash = b.block_hash AND l.block_num IS NULL;
+
+-- Upgrade: block_hash added after first release , backfilled from transaction
+ALTER TABLE eth.transaction_logs ADD COLUMN IF NOT EXISTS block_hash VARCHAR(255);
+UPDATE eth.transaction_logs l SET block_hash = t.block_hash FROM eth.transactions t
+WHERE l.tx_hash = t.tx_hash AND l.block_hash IS NULL;
+
+-- Upgrade: decoded added after first release
+ALTER TABLE eth.transaction_logs ADD COLUMN IF NOT EXISTS decoded JSONB;
+
+CREATE INDEX IF NOT EXISTS transaction_logs_address_idx ON eth.transaction_logs (address);
+CREATE INDEX IF NOT EXISTS transaction_logs_topic0_idx ON eth.transaction_logs (topic0);
+CREATE INDEX IF NOT EXISTS transaction_logs_block_num_idx ON eth.transaction_logs (block_num, log_index);
+
+-- Table: eth.token_transfers
+CREATE TABLE IF NOT EXISTS eth.token_transfers
+(
+    tx_hash      VARCHAR(255) NOT NULL REFERENCES eth.transactions (tx_hash) ON DELETE CASCADE,
+    block_hash   VARCHAR(255) NOT NULL,
+    block_num    BIGINT NOT NULL,
+    tx_index     BIGINT NOT NULL,
+    log_index    BIGINT NOT NULL,
+    token        VARCHAR(255) NOT NULL,
+    from_address VARCHAR(255) NOT NULL,
+    to_address   VARCHAR(255) NOT NULL,
+    value        NUMERIC(78, 0) NOT NULL,
+
+    created_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision),
+
+    PRIMARY KEY (block_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS token_transfers_token_idx ON eth.token_transfers (token, block_num, log_index);
+CREATE INDEX IF NOT EXISTS token_transfers_from_idx ON eth.token_transfers (from_address, block_num, log_index);
+CREATE INDEX IF NOT EXISTS token_transfers_to_idx ON eth.token_transfers (to_address, block_num, log_index);
+CREATE INDEX IF NOT EXISTS token_transfers_block_idx ON eth.token_transfers (block_num);
+
+-- Table: eth.nft_transfers
+CREATE TABLE IF NOT EXISTS eth.nft_transfers
+(
+    tx_hash      VARCHAR(255) NOT NULL REFERENCES eth.transactions (tx_hash) ON DELETE CASCADE,
+    block_hash   VARCHAR(255) NOT NULL,
+    block_num    BIGINT NOT NULL,
+    tx_index     BIGINT NOT NULL,
+    log_index    BIGINT NOT NULL,
+    batch_index  BIGINT NOT NULL DEFAULT 0,
+    token        VARCHAR(255) NOT NULL,
+    standard     VARCHAR(16) NOT NULL,
+    operator     VARCHAR(255),
+    from_address VARCHAR(255) NOT NULL,
+    to_address   VARCHAR(255) NOT NULL,
+    token_id     NUMERIC(78, 0) NOT NULL,
+    amount       NUMERIC(78, 0) NOT NULL,
+
+    created_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision),
+
+    PRIMARY KEY (block_hash, log_index, batch_index)
+);
+
+CREATE INDEX IF NOT EXISTS nft_transfers_token_idx ON eth.nft_transfers (token, token_id, block_num, log_index);
+CREATE INDEX IF NOT EXISTS nft_transfers_from_idx ON eth.nft_transfers (from_address, block_num, log_index);
+CREATE INDEX IF NOT EXISTS nft_transfers_to_idx ON eth.nft_transfers (to_address, block_num, log_index);
+
+-- View: eth.erc721_owners , current owner of ERC-721 token is receiver of its latest transfer
+CREATE OR REPLACE VIEW eth.erc721_owners AS
+SELECT DISTINCT ON (token, token_id) token, token_id, to_address AS owner, block_num, log_index
+FROM eth.nft_transfers
+WHERE standard = 'erc721'
+ORDER BY token, token_id, block_num DESC, log_index DESC;
+
+-- Table: eth.tokens , metadata of contracts emitting token transfers . Placeholder row with resolved false
+-- is inserted with first transfers of token
+CREATE TABLE IF NOT EXISTS eth.tokens
+(
+    address      VARCHAR(255) PRIMARY KEY,
+    name         TEXT,
+    symbol       TEXT,
+    decimals     SMALLINT,
+    total_supply NUMERIC(78, 0),
+    resolved     BOOL NOT NULL DEFAULT false,
+
+    -- failed_attempts counts resolve rounds failed on the node side since metadata was last saved
+    failed_attempts INT NOT NULL DEFAULT 0,
+
+    created_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision),
+    updated_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision)
+);
+
+-- Upgrade: resolved added after first release , rows saved before were all resolved .
+-- Tokens transferred before get a placeholder
+ALTER TABLE eth.tokens ADD COLUMN IF NOT EXISTS resolved BOOL NOT NULL DEFAULT true;
+ALTER TABLE eth.tokens ALTER COLUMN resolved SET DEFAULT false;
+INSERT INTO eth.tokens (address)
+SELECT token FROM eth.token_transfers
+UNION
+SELECT token FROM eth.nft_transfers
+ON CONFLICT DO NOTHING;
+
+-- Upgrade: failed_attempts added after first release , unresolved tokens are picked by it
+ALTER TABLE eth.tokens ADD COLUMN IF NOT EXISTS failed_attempts INT NOT NULL DEFAULT 0;
+DROP INDEX IF EXISTS eth.tokens_unresolved_idx;
+
+CREATE INDEX IF NOT EXISTS tokens_updated_at_idx ON eth.tokens (updated_at);
+CREATE INDEX IF NOT EXISTS tokens_unresolved_attempts_idx ON eth.tokens (failed_attempts, created_at) WHERE NOT resolved;
+
+-- Table: eth.token_balances , ledger of ERC-20 transfers applied up to checkpoint token_balance
+CREATE TABLE IF NOT EXISTS eth.token_balances
+(
+    token   VARCHAR(255) NOT NULL,
+    holder  VARCHAR(255) NOT NULL,
+    balance NUMERIC(78, 0) NOT NULL DEFAULT 0,
+
+    created_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision),
+    updated_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision),
+
+    PRIMARY KEY (token, holder)
+);
+
+CREATE INDEX IF NOT EXISTS token_balances_holder_idx ON eth.token_balances (holder, token);
+CREATE INDEX IF NOT EXISTS token_balances_token_balance_idx ON eth.token_balances (token, balance DESC);
+
+-- Table: eth.watchlist
+CREATE TABLE IF NOT EXISTS eth.watchlist
+(
+    address VARCHAR(255) PRIMARY KEY,
+    label   VARCHAR(255),
+
+    created_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision)
+);
+
+-- Table: eth.balance_snapshots
+CREATE TABLE IF NOT EXISTS eth.balance_snapshots
+(
+    address    VARCHAR(255) NOT NULL,
+    block_hash VARCHAR(255) NOT NULL REFERENCES eth.blocks (block_hash) ON DELETE CASCADE,
+    block_num  BIGINT NOT NULL,
+    block_time BIGINT NOT NULL,
+    balance    NUMERIC(78, 0) NOT NULL,
+
+    PRIMARY KEY (address, block_num)
+);
+
+CREATE INDEX IF NOT EXISTS balance_snapshots_time_idx ON eth.balance_snapshots (address, block_time);
+
+-- Table: eth.contract_abis
+CREATE TABLE IF NOT EXISTS eth.contract_abis
+(
+    address VARCHAR(255) PRIMARY KEY,
+    name    VARCHAR(255),
+    abi     JSONB NOT NULL,
+
+    created_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision),
+    updated_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision)
+);
+
+-- Table: eth.signatures
+CREATE TABLE IF NOT EXISTS eth.signatures
+(
+    id             BIGSERIAL PRIMARY KEY,
+    hash           VARCHAR(255) NOT NULL,
+    kind           VARCHAR(255) NOT NULL,
+    text_signature TEXT NOT NULL,
+
+    created_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision),
+    updated_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision),
+    UNIQUE (hash, text_signature)
+);
+
+-- Table: eth.sync_checkpoints
+CREATE TABLE IF NOT EXISTS eth.sync_checkpoints
+(
+    name       VARCHAR(255) PRIMARY KEY,
+    block_num  BIGINT NOT NULL,
+    block_hash VARCHAR(255) NOT NULL,
+
+    created_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision),
+    updated_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision)
+);
+
+-- Table: eth.reorg_events
+CREATE TABLE IF NOT EXISTS eth.reorg_events
+(
+    id            BIGSERIAL PRIMARY KEY,
+    ancestor_num  BIGINT NOT NULL,
+    ancestor_hash VARCHAR(255) NOT NULL,
+    depth         BIGINT NOT NULL,
+    old_head_hash VARCHAR(255) NOT NULL,
+    new_head_hash VARCHAR(255) NOT NULL,
+
+    created_at BIGINT DEFAULT (date_part('epoch'::text, now()) * (1000)::double precision)
 );
 
 
 ALTER TABLE eth.blocks OWNER to postgres;
+ALTER TABLE eth.uncles OWNER to postgres;
+ALTER TABLE eth.withdrawals OWNER to postgres;
 ALTER TABLE eth.transactions OWNER to postgres;
+ALTER TABLE eth.access_lists OWNER to postgres;
+ALTER TABLE eth.internal_transactions OWNER to postgres;
+ALTER TABLE eth.contracts OWNER to postgres;
 ALTER TABLE eth.receipts OWNER to postgres;
 ALTER TABLE eth.transaction_logs OWNER to postgres;
+ALTER TABLE eth.token_transfers OWNER to postgres;
+ALTER TABLE eth.nft_transfers OWNER to postgres;
+ALTER TABLE eth.erc721_owners OWNER to postgres;
+ALTER TABLE eth.tokens OWNER to postgres;
+ALTER TABLE eth.token_balances OWNER to postgres;
+ALTER TABLE eth.watchlist OWNER to postgres;
+ALTER TABLE eth.balance_snapshots OWNER to postgres;
+ALTER TABLE eth.contract_abis OWNER to postgres;
+ALTER TABLE eth.signatures OWNER to postgres;
+ALTER TABLE eth.sync_checkpoints OWNER to postgres;
+ALTER TABLE eth.reorg_events OWNER to postgres;
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ContractAbi is a user supplied abi of a contract , used to decode its transaction input and logs
+type ContractAbi struct {
+	Address string          `json:"address"`
+	Name    string          `json:"name"`
+	Abi     json.RawMessage `json:"abi" gorm:"serializer:json"`
+}
+
+// Decoded is a method call or event decoded with abi
+type Decoded struct {
+	Name      string       `json:"name"`
+	Signature string       `json:"signature"`
+	Args      []DecodedArg `json:"args"`
+}
+
+// DecodedArg is a typed argument of decoded method call or event .
+// Integers wider than 64 bits are decimal strings , bytes and hashes are 0x hex strings.
+type DecodedArg struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+type AbiRepository interface {
+	Save(ctx context.Context, contractAbi *ContractAbi) error
+	Get(ctx context.Context, address string) (*ContractAbi, error)
+	Delete(ctx context.Context, address string) error
+	List(ctx context.Context) ([]ContractAbi, error)
+}
+
+type AbiUseCase interface {
+	Save(ctx context.Context, contractAbi *ContractAbi) error
+	Get(ctx context.Context, address string) (*ContractAbi, error)
+	Delete(ctx context.Context, address string) error
+	List(ctx context.Context) ([]ContractAbi, error)
+}
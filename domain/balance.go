@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+)
+
+// CheckpointTokenBalance is the checkpoint of the last block applied to token balance ledger
+const CheckpointTokenBalance = "token_balance"
+
+// TokenBalance is the raw ERC-20 balance of a holder , summed from decoded transfers
+type TokenBalance struct {
+	Token   string `json:"token"`
+	Holder  string `json:"holder"`
+	Balance string `json:"balance"`
+}
+
+type BalanceRepository interface {
+	Apply(ctx context.Context, fromBlock uint64, toBlock uint64, blockHash string) error
+	RevertTo(ctx context.Context, blockNum uint64) error
+	ListByHolder(ctx context.Context, holder string) ([]TokenBalance, error)
+	ListHolders(ctx context.Context, token string, top int) ([]TokenBalance, error)
+}
+
+type BalanceUseCase interface {
+	Apply(ctx context.Context, fromBlock uint64, toBlock uint64, blockHash string) error
+	RevertTo(ctx context.Context, blockNum uint64) error
+	ListByHolder(ctx context.Context, holder string) ([]TokenBalance, error)
+	ListHolders(ctx context.Context, token string, top int) ([]TokenBalance, error)
+}
@@ -2,10 +2,20 @@ package domain
 
 import (
 	"context"
+	"encoding/json"
+)
+
+const (
+	BlockTagLatest       = "latest"
+	BlockTagLatestStable = "latest-stable"
+	BlockTagSafe         = "safe"
+	BlockTagFinalized    = "finalized"
 )
 
 type Block struct {
 	BlockDb
+	Safe              bool     `json:"safe" gorm:"-"`
+	Finalized         bool     `json:"finalized" gorm:"-"`
 	TransactionHashes []string `json:"transactions" gorm:"-"`
 }
 
@@ -14,21 +24,137 @@ type BlockDb struct {
 	BlockHash  string `json:"block_hash"`
 	BlockTime  uint64 `json:"block_time"`
 	ParentHash string `json:"parent_hash"`
+	TxCount    int    `json:"tx_count"`
 	Stable     bool   `json:"stable"`
+
+	Miner    string `json:"miner"`
+	GasLimit uint64 `json:"gas_limit"`
+	GasUsed  uint64 `json:"gas_used"`
+
+	// BaseFeePerGas is empty before London
+	BaseFeePerGas string `json:"base_fee_per_gas,omitempty"`
+
+	Difficulty string `json:"difficulty"`
+
+	// TotalDifficulty is empty when node does not report it
+	TotalDifficulty string `json:"total_difficulty,omitempty"`
+
+	ExtraData        []byte `json:"extra_data"`
+	Size             uint64 `json:"size"`
+	StateRoot        string `json:"state_root"`
+	TransactionsRoot string `json:"transactions_root"`
+	ReceiptsRoot     string `json:"receipts_root"`
+
+	// Sha3Uncles is the hash of uncle headers , empty for blocks indexed before it was stored
+	Sha3Uncles string `json:"sha3_uncles,omitempty"`
+
+	// WithdrawalsRoot is empty before Shanghai
+	WithdrawalsRoot string `json:"withdrawals_root,omitempty"`
+
+	// Nonce , MixHash and LogsBloom are empty for blocks indexed before they were stored
+	Nonce     string `json:"nonce,omitempty"`
+	MixHash   string `json:"mix_hash,omitempty"`
+	LogsBloom []byte `json:"logs_bloom,omitempty"`
+
+	Withdrawals []Withdrawal `json:"withdrawals,omitempty" gorm:"-"`
+	Uncles      []Uncle      `json:"uncles,omitempty" gorm:"-"`
+}
+
+// Uncle is an ommer header included by block , UncleIndex is its position in the including block
+type Uncle struct {
+	BlockHash  string `json:"-"`
+	BlockNum   uint64 `json:"-"`
+	UncleIndex int    `json:"index"`
+	UncleHash  string `json:"hash"`
+	UncleNum   uint64 `json:"number"`
+	Miner      string `json:"miner"`
+
+	// Header is the uncle header as node returned it , empty for uncles indexed before it was stored
+	Header json.RawMessage `json:"-" gorm:"serializer:json"`
+}
+
+// Withdrawal is a validator withdrawal of beacon chain included in block , amount in gwei
+type Withdrawal struct {
+	BlockHash       string `json:"-"`
+	BlockNum        uint64 `json:"block_num"`
+	WithdrawalIndex uint64 `json:"index"`
+	ValidatorIndex  uint64 `json:"validator_index"`
+	Address         string `json:"address"`
+	Amount          uint64 `json:"amount"`
+}
+
+// BlockFilter filters and pages stored blocks , nil bound means unbounded
+type BlockFilter struct {
+	FromBlock *uint64
+	ToBlock   *uint64
+	FromTime  *uint64
+	ToTime    *uint64
+
+	Desc  bool
+	Limit int
+
+	// AfterBlock is the block number of the last block of previous page
+	AfterBlock *uint64
+}
+
+// NewLatestBlockFilter returns a filter selecting the newest stored block
+func NewLatestBlockFilter() *BlockFilter {
+	return &BlockFilter{Desc: true, Limit: 1}
+}
+
+// ReorgEvent records a chain reorganization , blocks after AncestorNum were replaced
+type ReorgEvent struct {
+	AncestorNum  uint64 `json:"ancestor_num"`
+	AncestorHash string `json:"ancestor_hash"`
+	Depth        uint64 `json:"depth"`
+	OldHeadHash  string `json:"old_head_hash"`
+	NewHeadHash  string `json:"new_head_hash"`
+}
+
+// BlockGap is a range of block numbers missing in db , both ends included
+type BlockGap struct {
+	FromBlock uint64 `json:"from_block"`
+	ToBlock   uint64 `json:"to_block"`
+}
+
+// GapReportMaxBlockNum is the max number of blocks covered by one gap report
+const GapReportMaxBlockNum = 10000
+
+// GapReport describes holes of indexed blocks from FromBlock to ToBlock , both ends included .
+// A missing range starting in the window is reported whole.
+type GapReport struct {
+	FromBlock        uint64     `json:"from_block"`
+	ToBlock          uint64     `json:"to_block"`
+	MissingRanges    []BlockGap `json:"missing_ranges"`
+	MissingBlockNum  uint64     `json:"missing_block_num"`
+	IncompleteBlocks []uint64   `json:"incomplete_blocks"`
 }
 
 type BlockRepository interface {
-	List(ctx context.Context, limit int) ([]BlockDb, error)
+	List(ctx context.Context, filter *BlockFilter) ([]BlockDb, error)
 	Create(ctx context.Context, block *BlockDb) error
 	DeleteByNum(ctx context.Context, blockNum uint64) error
+	Reorg(ctx context.Context, event *ReorgEvent) error
 	SetStable(ctx context.Context, blockNum uint64, stable bool) error
 	GetByNumber(ctx context.Context, blockNum uint64) (*BlockDb, error)
+	GetByHash(ctx context.Context, blockHash string) (*BlockDb, error)
+	ListWithdrawals(ctx context.Context, blockHash string) ([]Withdrawal, error)
+	ListUncles(ctx context.Context, blockHash string) ([]Uncle, error)
+	GetLatestStable(ctx context.Context) (*BlockDb, error)
+	GetMinNumber(ctx context.Context, fromBlock uint64) (*uint64, error)
+	ListGaps(ctx context.Context, fromBlock uint64, toBlock uint64, limit int) ([]BlockGap, error)
+	ListIncomplete(ctx context.Context, fromBlock uint64, toBlock uint64, limit int) ([]uint64, error)
 }
 
 type BlockUseCase interface {
 	Create(ctx context.Context, block *BlockDb) error
 	SetStable(ctx context.Context, blockNum uint64, stable bool) error
 	DeleteByNum(ctx context.Context, blockNum uint64) error
-	List(ctx context.Context, limit int) ([]BlockDb, error)
+	Reorg(ctx context.Context, event *ReorgEvent) error
+	List(ctx context.Context, filter *BlockFilter) ([]BlockDb, error)
 	GetByNumber(ctx context.Context, blockNum uint64) (*Block, error)
+	GetByHash(ctx context.Context, blockHash string) (*Block, error)
+	GetByTag(ctx context.Context, tag string) (*Block, error)
+	GetByID(ctx context.Context, id string) (*Block, error)
+	GapReport(ctx context.Context, fromBlock uint64, toBlock uint64, limit int) (*GapReport, error)
 }
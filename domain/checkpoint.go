@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"context"
+)
+
+// Checkpoints of node safe and finalized block , recorded by scanner
+const (
+	CheckpointSafe      = "safe"
+	CheckpointFinalized = "finalized"
+)
+
+// SyncCheckpoint is the durable cursor of a scanner , every block up to BlockNum is fully indexed
+type SyncCheckpoint struct {
+	Name      string `json:"name"`
+	BlockNum  uint64 `json:"block_num"`
+	BlockHash string `json:"block_hash"`
+}
+
+type CheckpointRepository interface {
+	Get(ctx context.Context, name string) (*SyncCheckpoint, error)
+	Save(ctx context.Context, checkpoint *SyncCheckpoint) error
+}
+
+type CheckpointUseCase interface {
+	Get(ctx context.Context, name string) (*SyncCheckpoint, error)
+	Save(ctx context.Context, checkpoint *SyncCheckpoint) error
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+)
+
+// Contract is a contract creation with runtime bytecode at the end of creation block
+type Contract struct {
+	Address string `json:"address"`
+
+	// Creator is transaction sender for top level creation , creating contract for nested creation
+	Creator   string `json:"creator"`
+	TxHash    string `json:"creation_tx"`
+	BlockHash string `json:"-"`
+	BlockNum  uint64 `json:"block_num"`
+	TxIndex   uint   `json:"tx_index"`
+
+	// TraceIndex is the position of creating call in transaction call tree , 0 for top level creation
+	TraceIndex int    `json:"trace_index"`
+	Bytecode   []byte `json:"bytecode"`
+
+	// CodeHash is keccak256 of Bytecode , contracts sharing it are clones . Empty when no code is left to hash.
+	CodeHash string `json:"code_hash"`
+
+	// CodeAtLatest tells Bytecode was read at latest block , node no longer had state of creation block
+	CodeAtLatest bool `json:"code_at_latest"`
+}
+
+// ContractFilter filters contracts by creator or code hash , empty field matches anything
+type ContractFilter struct {
+	Creator  string
+	CodeHash string
+
+	Desc  bool
+	Limit int
+
+	// After is the position of the last contract of previous page
+	After *InternalTxPosition
+}
+
+type ContractRepository interface {
+	Save(ctx context.Context, contracts []Contract) error
+	Get(ctx context.Context, address string) (*Contract, error)
+	List(ctx context.Context, filter *ContractFilter) ([]Contract, error)
+}
+
+type ContractUseCase interface {
+	Save(ctx context.Context, contracts []Contract) error
+	Get(ctx context.Context, address string) (*Contract, error)
+	List(ctx context.Context, filter *ContractFilter) ([]Contract, error)
+}
@@ -6,12 +6,46 @@ import (
 
 var (
 	ErrBlockNotExist       = fmt.Errorf("block not exist")
+	ErrInvalidBlockID      = fmt.Errorf("invalid block id")
 	ErrTransactionNotExist = fmt.Errorf("transaction not exist")
+	ErrCheckpointNotExist  = fmt.Errorf("checkpoint not exist")
+	ErrReorgTooDeep        = fmt.Errorf("reorg deeper than max reorg depth")
+	ErrNoCommonAncestor    = fmt.Errorf("no stored common ancestor of reorg")
+	ErrBlockRangeTooLarge  = fmt.Errorf("block range too large")
+	ErrInvalidCursor       = fmt.Errorf("invalid cursor")
+	ErrInvalidLogFilter    = fmt.Errorf("invalid log filter")
+	ErrInvalidAddress      = fmt.Errorf("invalid address")
+	ErrInvalidTokenID      = fmt.Errorf("invalid token id")
+	ErrNftNotExist         = fmt.Errorf("nft not exist")
+	ErrWatchedNotExist     = fmt.Errorf("watched address not exist")
+	ErrInvalidAbi          = fmt.Errorf("invalid abi")
+	ErrAbiNotExist         = fmt.Errorf("abi not exist")
+	ErrUnauthorized        = fmt.Errorf("unauthorized")
+	ErrInvalidSignature    = fmt.Errorf("invalid signature")
+	ErrContractNotExist    = fmt.Errorf("contract not exist")
+	ErrInvalidCodeHash     = fmt.Errorf("invalid code hash")
+	ErrTokenNotExist       = fmt.Errorf("token not exist")
 )
 
 var ErrMap = map[error]ErrCode{
 	ErrBlockNotExist:       1001,
+	ErrInvalidBlockID:      1002,
 	ErrTransactionNotExist: 2001,
+	ErrCheckpointNotExist:  3001,
+	ErrBlockRangeTooLarge:  4001,
+	ErrInvalidCursor:       4002,
+	ErrInvalidLogFilter:    4003,
+	ErrInvalidAddress:      4004,
+	ErrInvalidTokenID:      4005,
+	ErrNftNotExist:         5001,
+	ErrWatchedNotExist:     6001,
+	ErrInvalidAbi:          4006,
+	ErrAbiNotExist:         7001,
+	ErrUnauthorized:        9001,
+	ErrInvalidSignature:    4007,
+	ErrContractNotExist:    8001,
+	ErrInvalidCodeHash:     4008,
+	ErrTokenNotExist:       5002,
 }
 
 type ErrorResponse struct {
@@ -25,5 +59,21 @@ type ErrMsg string
 
 var ErrMsgMap = map[ErrCode]ErrMsg{
 	1001: "block not exist",
+	1002: "invalid block id",
 	2001: "transaction not exist",
+	3001: "checkpoint not exist",
+	4001: "block range too large",
+	4002: "invalid cursor",
+	4003: "invalid log filter",
+	4004: "invalid address",
+	4005: "invalid token id",
+	5001: "nft not exist",
+	6001: "watched address not exist",
+	4006: "invalid abi",
+	7001: "abi not exist",
+	9001: "unauthorized",
+	4007: "invalid signature",
+	8001: "contract not exist",
+	4008: "invalid code hash",
+	5002: "token not exist",
 }
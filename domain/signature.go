@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+)
+
+const (
+	SignatureKindFunction = "function"
+	SignatureKindEvent    = "event"
+)
+
+// Signature maps a 4-byte function selector or 32-byte event topic to its text signature .
+// Different text signatures can collide on the same selector.
+type Signature struct {
+	Hash          string `json:"hash"`
+	Kind          string `json:"kind"`
+	TextSignature string `json:"text_signature"`
+}
+
+type SignatureRepository interface {
+	Save(ctx context.Context, signatures []Signature) error
+	ListByHashes(ctx context.Context, hashes []string) ([]Signature, error)
+}
+
+type SignatureUseCase interface {
+	Add(ctx context.Context, kind string, textSignatures []string) ([]Signature, error)
+	ListByHash(ctx context.Context, hash string) ([]Signature, error)
+	Lookup(ctx context.Context, hashes []string) (map[string]string, error)
+}
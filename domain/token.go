@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TokenTransfer is a decoded ERC-20 Transfer log , value is raw amount without decimals
+type TokenTransfer struct {
+	TxHash      string `json:"tx_hash"`
+	BlockHash   string `json:"-"`
+	BlockNum    uint64 `json:"block_num"`
+	TxIndex     uint   `json:"tx_index"`
+	LogIndex    int    `json:"log_index"`
+	Token       string `json:"token"`
+	FromAddress string `json:"from"`
+	ToAddress   string `json:"to"`
+	Value       string `json:"value"`
+
+	// ValueScaled is Value divided by 10^decimals of token , empty when decimals is unknown
+	ValueScaled string `json:"value_scaled,omitempty" gorm:"-"`
+	Symbol      string `json:"symbol,omitempty" gorm:"-"`
+}
+
+// Token is metadata of a contract emitting token transfers , read through contract calls .
+// Fields the contract does not implement are left empty.
+type Token struct {
+	Address     string  `json:"address"`
+	Name        string  `json:"name"`
+	Symbol      string  `json:"symbol"`
+	Decimals    *uint8  `json:"decimals"`
+	TotalSupply *string `json:"total_supply"`
+
+	// Resolved is false for placeholder saved with first transfers of token , until metadata is read
+	Resolved bool `json:"-"`
+
+	// UpdatedAt is the time metadata was last resolved , in unix milliseconds
+	UpdatedAt int64 `json:"updated_at" gorm:"->"`
+}
+
+// TokenTransferFilter filters token transfers by token and/or holder address
+type TokenTransferFilter struct {
+	// Token restricts transfers to this contract , empty matches any token
+	Token string
+
+	// Address restricts transfers to this holder , empty matches any holder
+	Address string
+
+	// Direction is one of TxDirectionFrom , TxDirectionTo and TxDirectionAny , only used with Address
+	Direction string
+
+	FromBlock uint64
+
+	// ToBlock nil means no upper bound
+	ToBlock *uint64
+
+	Desc  bool
+	Limit int
+
+	// After is the position of the last transfer of previous page
+	After *LogPosition
+}
+
+const (
+	NftStandardERC721  = "erc721"
+	NftStandardERC1155 = "erc1155"
+)
+
+// NftTransfer is a decoded ERC-721 Transfer or ERC-1155 TransferSingle / TransferBatch log .
+// Batch transfer is expanded into one row per token id , BatchIndex is the position in the batch.
+type NftTransfer struct {
+	TxHash      string `json:"tx_hash"`
+	BlockHash   string `json:"-"`
+	BlockNum    uint64 `json:"block_num"`
+	TxIndex     uint   `json:"tx_index"`
+	LogIndex    int    `json:"log_index"`
+	BatchIndex  int    `json:"batch_index"`
+	Token       string `json:"token"`
+	Standard    string `json:"standard"`
+	Operator    string `json:"operator,omitempty"`
+	FromAddress string `json:"from"`
+	ToAddress   string `json:"to"`
+	TokenID     string `json:"token_id"`
+	Amount      string `json:"amount"`
+}
+
+// NftTransferFilter filters nft transfers by token , token id and/or holder address
+type NftTransferFilter struct {
+	// Token restricts transfers to this contract , empty matches any token
+	Token string
+
+	// TokenID restricts transfers to this token id of Token , empty matches any token id
+	TokenID string
+
+	// Address restricts transfers to this holder , empty matches any holder
+	Address string
+
+	// Direction is one of TxDirectionFrom , TxDirectionTo and TxDirectionAny , only used with Address
+	Direction string
+
+	FromBlock uint64
+
+	// ToBlock nil means no upper bound
+	ToBlock *uint64
+
+	Desc  bool
+	Limit int
+
+	// After is the position of the last transfer of previous page
+	After *NftTransferPosition
+}
+
+// NftTransferPosition is the position of an nft transfer in the chain
+type NftTransferPosition struct {
+	BlockNum   uint64
+	LogIndex   int
+	BatchIndex int
+}
+
+// NftOwner is the current owner of an ERC-721 token , derived from its latest transfer
+type NftOwner struct {
+	Token    string `json:"token"`
+	TokenID  string `json:"token_id"`
+	Owner    string `json:"owner"`
+	BlockNum uint64 `json:"block_num"`
+	LogIndex int    `json:"log_index"`
+}
+
+// NftOwnerFilter pages ERC-721 tokens currently owned by an address , latest acquired first
+type NftOwnerFilter struct {
+	Owner string
+
+	// Token restricts tokens to this contract , empty matches any token
+	Token string
+
+	Limit int
+
+	// After is the position of acquiring transfer of the last token of previous page
+	After *LogPosition
+}
+
+type TokenRepository interface {
+	SaveTransfers(ctx context.Context, transfers []TokenTransfer) error
+	ListTransfers(ctx context.Context, filter *TokenTransferFilter) ([]TokenTransfer, error)
+	SaveNftTransfers(ctx context.Context, transfers []NftTransfer) error
+	ListNftTransfers(ctx context.Context, filter *NftTransferFilter) ([]NftTransfer, error)
+	GetNftOwner(ctx context.Context, token string, tokenID string) (*NftOwner, error)
+	ListNftsByOwner(ctx context.Context, filter *NftOwnerFilter) ([]NftOwner, error)
+	SaveTokens(ctx context.Context, tokens []Token) error
+	MarkTokensFailed(ctx context.Context, addresses []string) error
+	ListTokens(ctx context.Context, addresses []string) ([]Token, error)
+	ListUnresolvedTokens(ctx context.Context, limit int) ([]string, error)
+	ListStaleTokens(ctx context.Context, updatedBefore int64, limit int) ([]string, error)
+}
+
+type TokenUseCase interface {
+	SaveTransfers(ctx context.Context, transfers []TokenTransfer) error
+	ListTransfers(ctx context.Context, filter *TokenTransferFilter) ([]TokenTransfer, error)
+	SaveNftTransfers(ctx context.Context, transfers []NftTransfer) error
+	ListNftTransfers(ctx context.Context, filter *NftTransferFilter) ([]NftTransfer, error)
+	GetNftOwner(ctx context.Context, token string, tokenID string) (*NftOwner, error)
+	ListNftsByOwner(ctx context.Context, filter *NftOwnerFilter) ([]NftOwner, error)
+	SaveTokens(ctx context.Context, tokens []Token) error
+
+	// MarkTokensFailed records a resolve round of tokens failed on the node side , they are picked after other tokens
+	MarkTokensFailed(ctx context.Context, addresses []string) error
+
+	GetToken(ctx context.Context, address string) (*Token, error)
+
+	// ListTokensToResolve returns contracts without metadata first , then the ones resolved before staleBefore
+	ListTokensToResolve(ctx context.Context, staleBefore time.Time, limit int) ([]string, error)
+}
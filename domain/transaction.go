@@ -5,37 +5,216 @@ import (
 )
 
 type Transaction struct {
-	BlockHash string           `json:"-"`
-	TxHash    string           `json:"tx_hash"`
-	TxFrom    string           `json:"from"`
-	TxTo      string           `json:"to"`
-	Nonce     uint64           `json:"nonce"`
-	TxData    []byte           `json:"data"`
-	TxValue   string           `json:"value"`
-	Logs      []TransactionLog `json:"logs" gorm:"-"`
+	BlockHash string `json:"-"`
+	BlockNum  uint64 `json:"block_num"`
+	TxIndex   uint   `json:"tx_index"`
+	TxHash    string `json:"tx_hash"`
+	TxType    uint8  `json:"type"`
+	TxFrom    string `json:"from"`
+	TxTo      string `json:"to"`
+	Nonce     uint64 `json:"nonce"`
+	TxData    []byte `json:"data"`
+	TxValue   string `json:"value"`
+	ChainID   string `json:"chain_id"`
+	Gas       uint64 `json:"gas"`
+
+	// GasPrice is gas price of legacy and access list transaction , fee cap of dynamic fee transaction
+	GasPrice string `json:"gas_price"`
+
+	// MaxFeePerGas and MaxPriorityFeePerGas are only set for dynamic fee (EIP-1559) transaction
+	MaxFeePerGas         string `json:"max_fee_per_gas,omitempty"`
+	MaxPriorityFeePerGas string `json:"max_priority_fee_per_gas,omitempty"`
+
+	V string `json:"v"`
+	R string `json:"r"`
+	S string `json:"s"`
+
+	// Method is text signature of TxData selector from signature table
+	Method string `json:"method,omitempty" gorm:"-"`
+
+	// DecodedInput is TxData decoded with abi registered for TxTo
+	DecodedInput *Decoded `json:"decoded_input,omitempty" gorm:"serializer:json"`
+
+	AccessList []AccessListEntry `json:"access_list,omitempty" gorm:"-"`
+	Receipt    *Receipt          `json:"receipt" gorm:"-"`
+	Logs       []TransactionLog  `json:"logs" gorm:"-"`
+
+	// InternalTransactions is the flattened call tree , only set when internal transaction tracing is enabled
+	InternalTransactions []InternalTransaction `json:"internal_transactions,omitempty" gorm:"-"`
+}
+
+// InternalTransaction is one call frame of transaction call tree traced by callTracer
+type InternalTransaction struct {
+	TxHash    string `json:"tx_hash"`
+	BlockHash string `json:"-"`
+	BlockNum  uint64 `json:"block_num"`
+	TxIndex   uint   `json:"tx_index"`
+
+	// TraceIndex is the pre-order position of frame in call tree , top level call is 0
+	TraceIndex int `json:"trace_index"`
+
+	// TraceAddress is the child index path from top level call , empty for top level call
+	TraceAddress []int `json:"trace_address" gorm:"serializer:json"`
+	Depth        int   `json:"depth"`
+
+	// CallType is CALL , STATICCALL , DELEGATECALL , CALLCODE , CREATE , CREATE2 or SELFDESTRUCT
+	CallType    string `json:"type"`
+	FromAddress string `json:"from"`
+	ToAddress   string `json:"to"`
+	Value       string `json:"value"`
+	Gas         uint64 `json:"gas"`
+	GasUsed     uint64 `json:"gas_used"`
+	Input       []byte `json:"input"`
+	Output      []byte `json:"output"`
+	Error       string `json:"error,omitempty"`
+}
+
+// AccessListEntry is one address of EIP-2930 access list with its storage keys
+type AccessListEntry struct {
+	TxHash      string   `json:"-"`
+	EntryIndex  int      `json:"-"`
+	Address     string   `json:"address"`
+	StorageKeys []string `json:"storage_keys" gorm:"serializer:json"`
 }
 
 type Receipt struct {
-	TxHash string
+	TxHash            string `json:"-"`
+	TxType            uint8  `json:"type"`
+	Status            uint64 `json:"status"`
+	GasUsed           uint64 `json:"gas_used"`
+	CumulativeGasUsed uint64 `json:"cumulative_gas_used"`
+	EffectiveGasPrice string `json:"effective_gas_price"`
+	ContractAddress   string `json:"contract_address,omitempty"`
+	LogsBloom         []byte `json:"logs_bloom"`
 }
 
 type TransactionLog struct {
-	TxHash   string `json:"-"`
-	LogIndex int    `json:"index"`
-	LogData  []byte `json:"data"`
+	TxHash    string `json:"tx_hash"`
+	BlockHash string `json:"-"`
+	BlockNum  uint64 `json:"block_num"`
+	TxIndex   uint   `json:"tx_index"`
+	LogIndex  int    `json:"index"`
+	Address   string `json:"address"`
+	Topic0    string `json:"topic0,omitempty"`
+	Topic1    string `json:"topic1,omitempty"`
+	Topic2    string `json:"topic2,omitempty"`
+	Topic3    string `json:"topic3,omitempty"`
+	LogData   []byte `json:"data"`
+
+	// Event is text signature of Topic0 from signature table
+	Event string `json:"event,omitempty" gorm:"-"`
+
+	// Decoded is the event decoded with abi registered for Address
+	Decoded *Decoded `json:"decoded,omitempty" gorm:"serializer:json"`
+}
+
+// MaxLogTopics is the number of indexed topic positions of a log
+const MaxLogTopics = 4
+
+// LogFilter filters stored logs , same semantics as ethereum.FilterQuery
+type LogFilter struct {
+	FromBlock uint64
+	ToBlock   uint64
+
+	// Addresses restricts logs to these contracts , empty matches any contract
+	Addresses []string
+
+	// Topics[i] is the OR set of topic at position i , empty set matches anything
+	Topics [][]string
+
+	Limit int
+
+	// After is the position of the last log of previous page
+	After *LogPosition
+}
+
+// LogPosition is the position of a log in the chain
+type LogPosition struct {
+	BlockNum uint64
+	LogIndex int
+}
+
+const (
+	TxDirectionFrom = "from"
+	TxDirectionTo   = "to"
+	TxDirectionAny  = "any"
+)
+
+// AddressTxFilter filters transactions sent or received by an address
+type AddressTxFilter struct {
+	Address string
+
+	// Direction is one of TxDirectionFrom , TxDirectionTo and TxDirectionAny
+	Direction string
+
+	FromBlock uint64
+
+	// ToBlock nil means no upper bound
+	ToBlock *uint64
+
+	Desc  bool
+	Limit int
+
+	// After is the position of the last transaction of previous page
+	After *TxPosition
+}
+
+// TxPosition is the position of a transaction in the chain
+type TxPosition struct {
+	BlockNum uint64
+	TxIndex  uint
+}
+
+// InternalTxFilter filters internal transactions sent or received by an address , top level calls are excluded
+type InternalTxFilter struct {
+	Address string
+
+	// Direction is one of TxDirectionFrom , TxDirectionTo and TxDirectionAny
+	Direction string
+
+	FromBlock uint64
+
+	// ToBlock nil means no upper bound
+	ToBlock *uint64
+
+	Desc  bool
+	Limit int
+
+	// After is the position of the last internal transaction of previous page
+	After *InternalTxPosition
+}
+
+// InternalTxPosition is the position of an internal transaction in the chain
+type InternalTxPosition struct {
+	BlockNum   uint64
+	TxIndex    uint
+	TraceIndex int
 }
 
 type TransactionRepository interface {
 	Create(ctx context.Context, transaction *Transaction) error
 	GetTxHashesByBlockHash(ctx context.Context, blockHash string) ([]string, error)
-	SaveReceiptAndLogs(ctx context.Context, txHash string, logs []TransactionLog) error
+	ListByBlockHash(ctx context.Context, blockHash string) ([]Transaction, error)
+	ListAccessLists(ctx context.Context, txHashes []string) ([]AccessListEntry, error)
+	SaveReceiptAndLogs(ctx context.Context, receipt *Receipt, logs []TransactionLog) error
+	GetReceiptByTxHash(ctx context.Context, txHash string) (*Receipt, error)
 	GetLogsByTxHash(ctx context.Context, txHash string) ([]TransactionLog, error)
+	FilterLogs(ctx context.Context, filter *LogFilter) ([]TransactionLog, error)
+	ListByAddress(ctx context.Context, filter *AddressTxFilter) ([]Transaction, error)
 	GetByTxHash(ctx context.Context, txHash string) (*Transaction, error)
+	SaveInternalTransactions(ctx context.Context, internalTransactions []InternalTransaction) error
+	ListInternalTransactions(ctx context.Context, txHash string) ([]InternalTransaction, error)
+	ListInternalByAddress(ctx context.Context, filter *InternalTxFilter) ([]InternalTransaction, error)
 }
 
 type TransactionUseCase interface {
 	Create(ctx context.Context, transaction *Transaction) error
 	GetTxHashesByBlockHash(ctx context.Context, blockHash string) ([]string, error)
-	SaveReceiptAndLogs(ctx context.Context, txHash string, logs []TransactionLog) error
+	SaveReceiptAndLogs(ctx context.Context, receipt *Receipt, logs []TransactionLog) error
 	GetByTxHash(ctx context.Context, txHash string) (*Transaction, error)
+	FilterLogs(ctx context.Context, filter *LogFilter) ([]TransactionLog, error)
+	ListByBlockHash(ctx context.Context, blockHash string) ([]Transaction, error)
+	ListByAddress(ctx context.Context, filter *AddressTxFilter) ([]Transaction, error)
+	SaveInternalTransactions(ctx context.Context, internalTransactions []InternalTransaction) error
+	ListInternalByAddress(ctx context.Context, filter *InternalTxFilter) ([]InternalTransaction, error)
 }
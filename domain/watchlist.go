@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+)
+
+// WatchedAddress is an address whose native balance is recorded after every block it appears in
+type WatchedAddress struct {
+	Address string `json:"address"`
+	Label   string `json:"label"`
+}
+
+// BalanceSnapshot is native balance of a watched address at the end of a block , in wei
+type BalanceSnapshot struct {
+	Address   string `json:"-"`
+	BlockHash string `json:"-"`
+	BlockNum  uint64 `json:"block_num"`
+	BlockTime uint64 `json:"block_time"`
+	Balance   string `json:"balance"`
+}
+
+// SnapshotFilter filters and pages balance snapshots of an address , nil bound means unbounded
+type SnapshotFilter struct {
+	Address string
+
+	FromBlock *uint64
+	ToBlock   *uint64
+	FromTime  *uint64
+	ToTime    *uint64
+
+	Desc  bool
+	Limit int
+
+	// AfterBlock is the block number of the last snapshot of previous page
+	AfterBlock *uint64
+}
+
+type WatchlistRepository interface {
+	Add(ctx context.Context, watched *WatchedAddress) error
+	Remove(ctx context.Context, address string) error
+	List(ctx context.Context) ([]WatchedAddress, error)
+	SaveSnapshots(ctx context.Context, snapshots []BalanceSnapshot) error
+	ListSnapshots(ctx context.Context, filter *SnapshotFilter) ([]BalanceSnapshot, error)
+}
+
+type WatchlistUseCase interface {
+	Add(ctx context.Context, watched *WatchedAddress) error
+	Remove(ctx context.Context, address string) error
+	List(ctx context.Context) ([]WatchedAddress, error)
+	SaveSnapshots(ctx context.Context, snapshots []BalanceSnapshot) error
+	ListSnapshots(ctx context.Context, filter *SnapshotFilter) ([]BalanceSnapshot, error)
+}
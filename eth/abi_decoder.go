@@ -0,0 +1,193 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+	"github.com/ryanCool/ethService/domain"
+	"math/big"
+	"reflect"
+	"time"
+)
+
+// refreshAbis periodically reload registered abis , so uploaded abi is used for blocks indexed afterwards
+func (es *ethScan) refreshAbis(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := es.loadAbis(ctx); err != nil {
+				log.Err(err).Msg("reload abis fail")
+			}
+		case <-ctx.Done():
+			log.Print("break abi loop")
+			return
+		}
+	}
+}
+
+func (es *ethScan) loadAbis(ctx context.Context) error {
+	list, err := es.abiUcase.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	abis := make(map[common.Address]*abi.ABI, len(list))
+	for _, contractAbi := range list {
+		parsed, err := abi.JSON(bytes.NewReader(contractAbi.Abi))
+		if err != nil {
+			log.Warn().Err(err).Str("address", contractAbi.Address).Msg("parse abi fail")
+			continue
+		}
+		abis[common.HexToAddress(contractAbi.Address)] = &parsed
+	}
+
+	es.abiMu.Lock()
+	es.abis = abis
+	es.abiMu.Unlock()
+	return nil
+}
+
+// getAbi returns abi registered for contract , nil if none
+func (es *ethScan) getAbi(address common.Address) *abi.ABI {
+	es.abiMu.RLock()
+	defer es.abiMu.RUnlock()
+
+	return es.abis[address]
+}
+
+// decodeInput decode transaction input into method call , nil if no method of abi matches
+func decodeInput(parsed *abi.ABI, data []byte) *domain.Decoded {
+	if len(data) < 4 {
+		return nil
+	}
+
+	method, err := parsed.MethodById(data[:4])
+	if err != nil {
+		return nil
+	}
+
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		log.Debug().Err(err).Str("method", method.Sig).Msg("unpack input fail")
+		return nil
+	}
+
+	decoded := &domain.Decoded{Name: method.RawName, Signature: method.Sig, Args: []domain.DecodedArg{}}
+	for i, input := range method.Inputs {
+		decoded.Args = append(decoded.Args, domain.DecodedArg{
+			Name:  input.Name,
+			Type:  input.Type.String(),
+			Value: jsonValue(reflect.ValueOf(values[i])),
+		})
+	}
+	return decoded
+}
+
+// decodeLog decode log into event , nil if no event of abi matches .
+// Indexed arguments of dynamic type only keep their keccak256 hash in topic , hash is returned as value.
+func decodeLog(parsed *abi.ABI, l *types.Log) *domain.Decoded {
+	if len(l.Topics) == 0 {
+		return nil
+	}
+
+	event, err := parsed.EventByID(l.Topics[0])
+	if err != nil {
+		return nil
+	}
+
+	values, err := event.Inputs.NonIndexed().Unpack(l.Data)
+	if err != nil {
+		log.Debug().Err(err).Str("event", event.Sig).Msg("unpack log data fail")
+		return nil
+	}
+
+	decoded := &domain.Decoded{Name: event.RawName, Signature: event.Sig, Args: []domain.DecodedArg{}}
+	topicIndex, dataIndex := 1, 0
+	for _, input := range event.Inputs {
+		arg := domain.DecodedArg{Name: input.Name, Type: input.Type.String()}
+		if !input.Indexed {
+			arg.Value = jsonValue(reflect.ValueOf(values[dataIndex]))
+			dataIndex++
+			decoded.Args = append(decoded.Args, arg)
+			continue
+		}
+
+		if topicIndex >= len(l.Topics) {
+			return nil
+		}
+		topic := l.Topics[topicIndex]
+		topicIndex++
+
+		switch input.Type.T {
+		case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+			arg.Value = topic.String()
+		default:
+			out := map[string]interface{}{}
+			if err := abi.ParseTopicsIntoMap(out, abi.Arguments{input}, []common.Hash{topic}); err != nil {
+				return nil
+			}
+			arg.Value = jsonValue(reflect.ValueOf(out[input.Name]))
+		}
+		decoded.Args = append(decoded.Args, arg)
+	}
+
+	return decoded
+}
+
+// jsonValue convert unpacked abi value to json friendly value
+func jsonValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch value := v.Interface().(type) {
+	case *big.Int:
+		if value == nil {
+			return nil
+		}
+		return value.String()
+	case common.Address:
+		return value.String()
+	case common.Hash:
+		return value.String()
+	case []byte:
+		return hexutil.Encode(value)
+	}
+
+	switch v.Kind() {
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return hexutil.Encode(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		res := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			res[i] = jsonValue(v.Index(i))
+		}
+		return res
+	case reflect.Struct:
+		res := map[string]interface{}{}
+		for i := 0; i < v.NumField(); i++ {
+			name := v.Type().Field(i).Tag.Get("json")
+			if name == "" {
+				name = v.Type().Field(i).Name
+			}
+			res[name] = jsonValue(v.Field(i))
+		}
+		return res
+	case reflect.Ptr:
+		return jsonValue(v.Elem())
+	}
+
+	return v.Interface()
+}
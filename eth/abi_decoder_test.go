@@ -0,0 +1,191 @@
+package eth
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+)
+
+var (
+	testFrom = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	testTo   = common.HexToAddress("0x00000000000000000000000000000000000000b2")
+)
+
+func loadTestAbi(t *testing.T) *abi.ABI {
+	f, err := os.Open("testdata/decoder_abi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	parsed, err := abi.JSON(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &parsed
+}
+
+// assertJSON compare value with expected json , so decoded values are checked the way api returns them
+func assertJSON(t *testing.T, value interface{}, want string) {
+	t.Helper()
+
+	got, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Fatalf("got %s\nwant %s", got, want)
+	}
+}
+
+func TestDecodeInput(t *testing.T) {
+	parsed := loadTestAbi(t)
+
+	transfer, err := parsed.Pack("transfer", testTo, big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	route := struct {
+		Pool common.Address
+		Fee  *big.Int
+	}{testFrom, big.NewInt(3000)}
+	setRoute, err := parsed.Pack("setRoute", route, []byte{0xca, 0xfe}, [4]byte{1, 2, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{
+			name: "static arguments",
+			data: transfer,
+			want: `{"name":"transfer","signature":"transfer(address,uint256)","args":[
+				{"name":"to","type":"address","value":"` + testTo.String() + `"},
+				{"name":"amount","type":"uint256","value":"1000"}]}`,
+		},
+		{
+			name: "tuple , bytes and fixed bytes",
+			data: setRoute,
+			want: `{"name":"setRoute","signature":"setRoute((address,uint24),bytes,bytes4)","args":[
+				{"name":"route","type":"(address,uint24)","value":{"pool":"` + testFrom.String() + `","fee":"3000"}},
+				{"name":"path","type":"bytes","value":"0xcafe"},
+				{"name":"salt","type":"bytes4","value":"0x01020304"}]}`,
+		},
+		{name: "unknown selector", data: append([]byte{0xde, 0xad, 0xbe, 0xef}, transfer[4:]...), want: `null`},
+		{name: "shorter than selector", data: transfer[:3], want: `null`},
+		{name: "truncated arguments", data: transfer[:20], want: `null`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertJSON(t, decodeInput(parsed, c.data), c.want)
+		})
+	}
+}
+
+func TestDecodeLog(t *testing.T) {
+	parsed := loadTestAbi(t)
+
+	transferData, err := parsed.Events["Transfer"].Inputs.NonIndexed().Pack(big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registeredData, err := parsed.Events["Registered"].Inputs.NonIndexed().Pack([]common.Address{testFrom, testTo})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transferTopics := []common.Hash{
+		parsed.Events["Transfer"].ID,
+		common.BytesToHash(testFrom.Bytes()),
+		common.BytesToHash(testTo.Bytes()),
+	}
+	nameHash := crypto.Keccak256Hash([]byte("vitalik"))
+
+	cases := []struct {
+		name string
+		log  *types.Log
+		want string
+	}{
+		{
+			name: "indexed addresses",
+			log:  &types.Log{Topics: transferTopics, Data: transferData},
+			want: `{"name":"Transfer","signature":"Transfer(address,address,uint256)","args":[
+				{"name":"from","type":"address","value":"` + testFrom.String() + `"},
+				{"name":"to","type":"address","value":"` + testTo.String() + `"},
+				{"name":"value","type":"uint256","value":"42"}]}`,
+		},
+		{
+			name: "indexed dynamic argument keeps topic hash",
+			log: &types.Log{
+				Topics: []common.Hash{parsed.Events["Registered"].ID, nameHash, common.BigToHash(big.NewInt(7))},
+				Data:   registeredData,
+			},
+			want: `{"name":"Registered","signature":"Registered(string,uint64,address[])","args":[
+				{"name":"name","type":"string","value":"` + nameHash.String() + `"},
+				{"name":"id","type":"uint64","value":7},
+				{"name":"owners","type":"address[]","value":["` + testFrom.String() + `","` + testTo.String() + `"]}]}`,
+		},
+		{name: "no topic", log: &types.Log{Data: transferData}, want: `null`},
+		{name: "unknown event", log: &types.Log{Topics: []common.Hash{nameHash}, Data: transferData}, want: `null`},
+		{name: "missing indexed topic", log: &types.Log{Topics: transferTopics[:2], Data: transferData}, want: `null`},
+		{name: "truncated data", log: &types.Log{Topics: transferTopics, Data: transferData[:16]}, want: `null`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertJSON(t, decodeLog(parsed, c.log), c.want)
+		})
+	}
+}
+
+func TestJsonValue(t *testing.T) {
+	type tagged struct {
+		Amount *big.Int `json:"amount"`
+		Owner  common.Address
+	}
+
+	var nilInt *big.Int
+	cases := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"big int beyond 64 bits", new(big.Int).Lsh(big.NewInt(1), 70), `"1180591620717411303424"`},
+		{"nil big int", nilInt, `null`},
+		{"address", testTo, `"` + testTo.String() + `"`},
+		{"hash", common.BigToHash(big.NewInt(1)), `"0x0000000000000000000000000000000000000000000000000000000000000001"`},
+		{"bytes", []byte{0xab, 0xcd}, `"0xabcd"`},
+		{"fixed bytes", [3]byte{1, 2, 3}, `"0x010203"`},
+		{"array of int", [2]uint16{1, 2}, `[1,2]`},
+		{"slice of big int", []*big.Int{big.NewInt(1), big.NewInt(2)}, `["1","2"]`},
+		{"struct uses json tag or field name", tagged{big.NewInt(5), testFrom}, `{"amount":"5","Owner":"` + testFrom.String() + `"}`},
+		{"bool", true, `true`},
+		{"invalid", nil, `null`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertJSON(t, jsonValue(reflect.ValueOf(c.value)), c.want)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package eth
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"github.com/ryanCool/ethService/domain"
+	"time"
+)
+
+// balanceApplyBlockNum is the max number of blocks applied to balance ledger in one round
+const balanceApplyBlockNum = 1000
+
+// applyBalances periodically apply token transfers of fully indexed blocks to balance ledger in block order
+func (es *ethScan) applyBalances(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := es.applyBalanceRound(ctx); err != nil {
+				log.Err(err).Msg("apply token balances fail")
+			}
+		case <-ctx.Done():
+			log.Print("break balance ledger loop")
+			return
+		}
+	}
+}
+
+// applyBalanceRound apply blocks after ledger checkpoint , stopping before the first missing or incomplete block
+func (es *ethScan) applyBalanceRound(ctx context.Context) error {
+	es.ledgerMu.Lock()
+	defer es.ledgerMu.Unlock()
+
+	from := syncFromNBlock.Uint64()
+	applied, err := es.checkpointUcase.Get(ctx, domain.CheckpointTokenBalance)
+	if err != nil && err != domain.ErrCheckpointNotExist {
+		return err
+	}
+	if applied != nil {
+		from = applied.BlockNum + 1
+	}
+
+	synced, err := es.checkpointUcase.Get(ctx, checkpointName)
+	if err == domain.ErrCheckpointNotExist {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if synced.BlockNum < from {
+		return nil
+	}
+
+	to := synced.BlockNum
+	if to-from+1 > balanceApplyBlockNum {
+		to = from + balanceApplyBlockNum - 1
+	}
+
+	//blocks under sync checkpoint can still be missing or incomplete while they are replaced
+	report, err := es.blockUCase.GapReport(ctx, from, to, 1)
+	if err != nil {
+		return err
+	}
+	end := to + 1
+	for _, gap := range report.MissingRanges {
+		if gap.FromBlock < end {
+			end = gap.FromBlock
+		}
+	}
+	for _, blockNum := range report.IncompleteBlocks {
+		if blockNum < end {
+			end = blockNum
+		}
+	}
+
+	if end <= from {
+		return nil
+	}
+	to = end - 1
+
+	block, err := es.blockUCase.GetByNumber(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	if err = es.balanceUcase.Apply(ctx, from, to, block.BlockHash); err != nil {
+		return err
+	}
+
+	log.Debug().Uint64("from", from).Uint64("to", to).Msg("apply token balances")
+	return nil
+}
+
+// deleteBlock revert balance ledger to the block before blockNum , then delete the block .
+// Transfers of a deleted block are gone by cascade , so they must leave the ledger first.
+func (es *ethScan) deleteBlock(ctx context.Context, blockNum uint64) error {
+	es.ledgerMu.Lock()
+	defer es.ledgerMu.Unlock()
+
+	if blockNum > 0 {
+		if err := es.balanceUcase.RevertTo(ctx, blockNum-1); err != nil {
+			return err
+		}
+	}
+
+	return es.blockUCase.DeleteByNum(ctx, blockNum)
+}
+
+// reorgBlocks revert balance ledger to the common ancestor , then replace orphaned blocks
+func (es *ethScan) reorgBlocks(ctx context.Context, event *domain.ReorgEvent) error {
+	es.ledgerMu.Lock()
+	defer es.ledgerMu.Unlock()
+
+	if err := es.balanceUcase.RevertTo(ctx, event.AncestorNum); err != nil {
+		return err
+	}
+
+	return es.blockUCase.Reorg(ctx, event)
+}
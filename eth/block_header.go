@@ -0,0 +1,168 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ryanCool/ethService/domain"
+	"math/big"
+)
+
+// nodeHeader is a header with the block hash node reported . types.Header of this geth version does not decode
+// post-Shanghai fields , so header.Hash() computed from it is not the chain's hash . Field Hash shadows that method.
+type nodeHeader struct {
+	*types.Header
+	Hash common.Hash
+}
+
+func (h *nodeHeader) UnmarshalJSON(data []byte) error {
+	var head types.Header
+	if err := json.Unmarshal(data, &head); err != nil {
+		return err
+	}
+
+	var hash struct {
+		Hash *common.Hash `json:"hash"`
+	}
+	if err := json.Unmarshal(data, &hash); err != nil {
+		return err
+	}
+	if hash.Hash == nil {
+		return errors.New("missing required field 'hash' for header")
+	}
+
+	h.Header, h.Hash = &head, *hash.Hash
+	return nil
+}
+
+// fetchHeader fetch header of block number or tag , e.g. latest
+func (es *ethScan) fetchHeader(ctx context.Context, number string) (*nodeHeader, error) {
+	var head *nodeHeader
+	if err := es.rpcRawClient.CallContext(ctx, &head, "eth_getBlockByNumber", number, false); err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, ethereum.NotFound
+	}
+	return head, nil
+}
+
+// rawHeader holds block fields node reports but types.Header of this geth version does not decode ,
+// with block body decoded from the same response
+type rawHeader struct {
+	Hash            common.Hash          `json:"hash"`
+	Size            hexutil.Uint64       `json:"size"`
+	TotalDifficulty *hexutil.Big         `json:"totalDifficulty"`
+	WithdrawalsRoot *common.Hash         `json:"withdrawalsRoot"`
+	Withdrawals     []rawWithdrawal      `json:"withdrawals"`
+	UncleHashes     []common.Hash        `json:"uncles"`
+	Transactions    []*types.Transaction `json:"transactions"`
+
+	// UncleHeaders are uncle headers as node returned them , fetched apart from block response
+	UncleHeaders []json.RawMessage `json:"-"`
+}
+
+type rawWithdrawal struct {
+	Index     hexutil.Uint64 `json:"index"`
+	Validator hexutil.Uint64 `json:"validatorIndex"`
+	Address   common.Address `json:"address"`
+	Amount    hexutil.Uint64 `json:"amount"`
+}
+
+// fetchBlock fetch block with full transactions and the fields types.Header does not decode .
+// Everything is decoded from one eth_getBlockByNumber response , so all fields belong to the same block even during reorg.
+func (es *ethScan) fetchBlock(ctx context.Context, blockNum *big.Int) (*types.Block, *rawHeader, error) {
+	var raw json.RawMessage
+	if err := es.rpcRawClient.CallContext(ctx, &raw, "eth_getBlockByNumber", hexutil.EncodeBig(blockNum), true); err != nil {
+		return nil, nil, err
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil, ethereum.NotFound
+	}
+
+	var head *types.Header
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return nil, nil, err
+	}
+
+	var body rawHeader
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, nil, err
+	}
+
+	uncleHeaders, err := es.fetchUncles(ctx, body.Hash, len(body.UncleHashes))
+	if err != nil {
+		return nil, nil, err
+	}
+	body.UncleHeaders = uncleHeaders
+
+	uncles := make([]*types.Header, len(body.UncleHeaders))
+	for i, raw := range body.UncleHeaders {
+		if err := json.Unmarshal(raw, &uncles[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return types.NewBlockWithHeader(head).WithBody(body.Transactions, uncles), &body, nil
+}
+
+// fetchUncles fetch uncle headers of block by its hash , block response only lists their hashes
+func (es *ethScan) fetchUncles(ctx context.Context, blockHash common.Hash, count int) ([]json.RawMessage, error) {
+	if count == 0 {
+		return nil, nil
+	}
+
+	uncles := make([]json.RawMessage, count)
+	reqs := make([]rpc.BatchElem, count)
+	for i := range reqs {
+		reqs[i] = rpc.BatchElem{
+			Method: "eth_getUncleByBlockHashAndIndex",
+			Args:   []interface{}{blockHash, hexutil.EncodeUint64(uint64(i))},
+			Result: &uncles[i],
+		}
+	}
+
+	if err := es.rpcRawClient.BatchCallContext(ctx, reqs); err != nil {
+		return nil, err
+	}
+
+	for i := range reqs {
+		if reqs[i].Error != nil {
+			return nil, reqs[i].Error
+		}
+		if len(uncles[i]) == 0 || string(uncles[i]) == "null" {
+			return nil, fmt.Errorf("got null header for uncle %d of block %s", i, blockHash)
+		}
+	}
+
+	return uncles, nil
+}
+
+// setRawHeader fill block fields only available from raw header
+func setRawHeader(blockDb *domain.BlockDb, head *rawHeader) {
+	blockDb.Size = uint64(head.Size)
+	if head.TotalDifficulty != nil {
+		blockDb.TotalDifficulty = head.TotalDifficulty.ToInt().String()
+	}
+
+	if head.WithdrawalsRoot != nil {
+		blockDb.WithdrawalsRoot = head.WithdrawalsRoot.String()
+	}
+
+	for _, w := range head.Withdrawals {
+		blockDb.Withdrawals = append(blockDb.Withdrawals, domain.Withdrawal{
+			BlockHash:       blockDb.BlockHash,
+			BlockNum:        blockDb.BlockNum,
+			WithdrawalIndex: uint64(w.Index),
+			ValidatorIndex:  uint64(w.Validator),
+			Address:         w.Address.String(),
+			Amount:          uint64(w.Amount),
+		})
+	}
+}
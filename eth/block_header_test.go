@@ -0,0 +1,74 @@
+package eth
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/core/types"
+	"os"
+	"testing"
+)
+
+// loadShanghaiHeaders read two consecutive post-Shanghai headers of testdata/shanghai_headers.json as node returns them ,
+// hashes cover withdrawalsRoot which types.Header of this geth version drops
+func loadShanghaiHeaders(t *testing.T) []json.RawMessage {
+	data, err := os.ReadFile("testdata/shanghai_headers.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var headers []json.RawMessage
+	if err := json.Unmarshal(data, &headers); err != nil {
+		t.Fatal(err)
+	}
+	return headers
+}
+
+func TestNodeHeader(t *testing.T) {
+	raw := loadShanghaiHeaders(t)
+
+	var parent, head *nodeHeader
+	if err := json.Unmarshal(raw[0], &parent); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(raw[1], &head); err != nil {
+		t.Fatal(err)
+	}
+
+	if head.Hash.String() != "0x36f5c622d81e060741c1666bcf9292706079fca6471bd6e3c6ef161e9fece474" || head.Number.Uint64() != 17034871 {
+		t.Fatalf("decoded header %d %s", head.Number, head.Hash)
+	}
+	if head.ParentHash != parent.Hash {
+		t.Fatalf("parent hash %s , want node hash of parent %s", head.ParentHash, parent.Hash)
+	}
+
+	//locally computed hash misses withdrawalsRoot
+	if head.Header.Hash() == head.Hash {
+		t.Fatal("local header hash matches node hash , fixture is not post-Shanghai")
+	}
+
+	var missing *nodeHeader
+	if err := json.Unmarshal([]byte(`{"number":"0x1"}`), &missing); err == nil {
+		t.Fatal("header without hash accepted")
+	}
+}
+
+func TestWrapBlockDbUsesNodeHash(t *testing.T) {
+	raw := loadShanghaiHeaders(t)
+
+	var header *types.Header
+	if err := json.Unmarshal(raw[1], &header); err != nil {
+		t.Fatal(err)
+	}
+	var body rawHeader
+	if err := json.Unmarshal(raw[1], &body); err != nil {
+		t.Fatal(err)
+	}
+
+	blockDb := wrapBlockDb(types.NewBlockWithHeader(header), &body, false)
+	if blockDb.BlockHash != body.Hash.String() {
+		t.Fatalf("block hash %s , want node hash %s", blockDb.BlockHash, body.Hash)
+	}
+
+	if blockDb.Nonce != "0x0000000000000000" || blockDb.MixHash != header.MixDigest.String() || len(blockDb.LogsBloom) != types.BloomByteLength {
+		t.Fatalf("nonce %s , mix hash %s , logs bloom %d bytes", blockDb.Nonce, blockDb.MixHash, len(blockDb.LogsBloom))
+	}
+}
@@ -0,0 +1,117 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ryanCool/ethService/domain"
+)
+
+var errTraceMismatch = errors.New("block trace does not match fetched block")
+
+// callTracerConfig is the debug_traceBlockByNumber option selecting geth built-in call tracer
+var callTracerConfig = map[string]interface{}{"tracer": "callTracer"}
+
+// txTrace is the trace result of one transaction , older nodes omit txHash
+type txTrace struct {
+	TxHash *common.Hash `json:"txHash"`
+	Result *callFrame   `json:"result"`
+	Error  string       `json:"error"`
+}
+
+// callFrame is one call of callTracer output with its nested calls
+type callFrame struct {
+	Type    string          `json:"type"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to"`
+	Value   *hexutil.Big    `json:"value"`
+	Gas     hexutil.Uint64  `json:"gas"`
+	GasUsed hexutil.Uint64  `json:"gasUsed"`
+	Input   hexutil.Bytes   `json:"input"`
+	Output  hexutil.Bytes   `json:"output"`
+	Error   string          `json:"error"`
+	Calls   []callFrame     `json:"calls"`
+}
+
+// saveInternalTransactions trace block with callTracer and save flattened call trees of its transactions ,
+// with contracts created by nested calls
+func (es *ethScan) saveInternalTransactions(ctx context.Context, block *domain.BlockDb, rawBlock *types.Block) error {
+	var traces []txTrace
+	if err := es.rpcRawClient.CallContext(ctx, &traces, "debug_traceBlockByNumber", hexutil.EncodeBig(rawBlock.Number()), callTracerConfig); err != nil {
+		return err
+	}
+
+	internalTransactions, err := flattenBlockTrace(block, rawBlock.Transactions(), traces)
+	if err != nil {
+		return err
+	}
+
+	if err := es.transactionUcase.SaveInternalTransactions(ctx, internalTransactions); err != nil {
+		return err
+	}
+
+	return es.saveTracedContracts(ctx, internalTransactions)
+}
+
+// flattenBlockTrace flatten call trees of block transactions in pre-order .
+// Block is traced by number , so traces are matched against fetched transactions to make sure both calls saw the same block.
+func flattenBlockTrace(block *domain.BlockDb, transactions types.Transactions, traces []txTrace) ([]domain.InternalTransaction, error) {
+	if len(traces) != len(transactions) {
+		return nil, errTraceMismatch
+	}
+
+	var res []domain.InternalTransaction
+	for i, trace := range traces {
+		if trace.TxHash != nil && *trace.TxHash != transactions[i].Hash() {
+			return nil, errTraceMismatch
+		}
+
+		if trace.Result == nil {
+			return nil, errors.New("trace transaction fail: " + trace.Error)
+		}
+
+		base := domain.InternalTransaction{
+			TxHash:    transactions[i].Hash().String(),
+			BlockHash: block.BlockHash,
+			BlockNum:  block.BlockNum,
+			TxIndex:   uint(i),
+		}
+		res = append(res, flattenCallFrame(nil, base, trace.Result, []int{})...)
+	}
+
+	return res, nil
+}
+
+// flattenCallFrame append frame and its nested calls to call list of one transaction , traceAddress is the child index path of frame
+func flattenCallFrame(list []domain.InternalTransaction, base domain.InternalTransaction, frame *callFrame, traceAddress []int) []domain.InternalTransaction {
+	internalTransaction := base
+	internalTransaction.TraceIndex = len(list)
+	internalTransaction.TraceAddress = traceAddress
+	internalTransaction.Depth = len(traceAddress)
+	internalTransaction.CallType = frame.Type
+	internalTransaction.FromAddress = frame.From.String()
+	internalTransaction.Value = "0"
+	internalTransaction.Gas = uint64(frame.Gas)
+	internalTransaction.GasUsed = uint64(frame.GasUsed)
+	internalTransaction.Input = frame.Input
+	internalTransaction.Output = frame.Output
+	internalTransaction.Error = frame.Error
+
+	if frame.To != nil {
+		internalTransaction.ToAddress = frame.To.String()
+	}
+	if frame.Value != nil {
+		internalTransaction.Value = frame.Value.ToInt().String()
+	}
+
+	list = append(list, internalTransaction)
+	for i := range frame.Calls {
+		childAddress := make([]int, len(traceAddress), len(traceAddress)+1)
+		copy(childAddress, traceAddress)
+		list = flattenCallFrame(list, base, &frame.Calls[i], append(childAddress, i))
+	}
+
+	return list
+}
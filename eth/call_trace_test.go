@@ -0,0 +1,212 @@
+package eth
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ryanCool/ethService/domain"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+)
+
+var traceBlock = &domain.BlockDb{BlockNum: 16432500, BlockHash: "0x6a2dd2f4be9a2b2e9ac3c6dc0db0d9e4e2f1a0c3e7b5d6a8f9e0c1b2a3d4e5f6"}
+
+// traceTransactions returns the transactions traced in testdata/block_trace.json , in block order
+func traceTransactions() types.Transactions {
+	router := common.HexToAddress("0x7a250d5630b4cf539739df2c5dacb4c659f2488d")
+	return types.Transactions{
+		types.NewTx(&types.LegacyTx{Nonce: 7, GasPrice: big.NewInt(30e9), Gas: 300000, To: &router, Value: big.NewInt(1e18)}),
+		types.NewTx(&types.LegacyTx{Nonce: 8, GasPrice: big.NewInt(30e9), Gas: 60000, Data: []byte{0x60, 0x80}}),
+	}
+}
+
+// loadBlockTrace read debug_traceBlockByNumber callTracer response of testdata/block_trace.json
+func loadBlockTrace(t *testing.T) []txTrace {
+	data, err := os.ReadFile("testdata/block_trace.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp struct {
+		Result []txTrace `json:"result"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Result
+}
+
+// tracePosition is the part of flattened call checked by tests
+type tracePosition struct {
+	TxIndex      uint
+	TraceIndex   int
+	TraceAddress []int
+	Depth        int
+	CallType     string
+	ToAddress    string
+	Value        string
+	Error        string
+}
+
+func TestFlattenBlockTrace(t *testing.T) {
+	transactions := traceTransactions()
+	res, err := flattenBlockTrace(traceBlock, transactions, loadBlockTrace(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []tracePosition{
+		{0, 0, []int{}, 0, "CALL", "0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D", "1000000000000000000", ""},
+		{0, 1, []int{0}, 1, "STATICCALL", "0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc", "0", ""},
+		{0, 2, []int{1}, 1, "CALL", "0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f", "1000000000000000000", ""},
+		{0, 3, []int{1, 0}, 2, "CREATE2", "0xA478c2975Ab1Ea89e8196811F51A7B7Ade33eB11", "0", ""},
+		{0, 4, []int{2}, 1, "CALL", "0x0d4a11d5EEaaC28EC3F61d100daF4d40471f1852", "0", "execution reverted"},
+		{0, 5, []int{2, 0}, 2, "CREATE", "0x3139Ffc91B99aa94DA8A2dc13f1fC36F9BDc98eE", "0", ""},
+		{1, 0, []int{}, 0, "CREATE", "", "0", "out of gas"},
+	}
+
+	if len(res) != len(want) {
+		t.Fatalf("got %d calls , want %d", len(res), len(want))
+	}
+
+	for i, call := range res {
+		got := tracePosition{call.TxIndex, call.TraceIndex, call.TraceAddress, call.Depth, call.CallType, call.ToAddress, call.Value, call.Error}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("call %d = %+v , want %+v", i, got, want[i])
+		}
+
+		if call.TxHash != transactions[call.TxIndex].Hash().String() || call.BlockHash != traceBlock.BlockHash || call.BlockNum != traceBlock.BlockNum {
+			t.Errorf("call %d carries wrong position %s %s %d", i, call.TxHash, call.BlockHash, call.BlockNum)
+		}
+	}
+
+	if res[0].FromAddress != "0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B" || res[0].Gas != 300000 || res[0].GasUsed != 175000 {
+		t.Errorf("top level call = %+v", res[0])
+	}
+}
+
+func TestFlattenBlockTraceError(t *testing.T) {
+	transactions := traceTransactions()
+
+	cases := []struct {
+		name   string
+		traces func(traces []txTrace) []txTrace
+		want   string
+	}{
+		{
+			name:   "trace count differs from transactions",
+			traces: func(traces []txTrace) []txTrace { return traces[:1] },
+			want:   errTraceMismatch.Error(),
+		},
+		{
+			name: "tx hash differs from block transaction",
+			traces: func(traces []txTrace) []txTrace {
+				return []txTrace{traces[1], traces[0]}
+			},
+			want: errTraceMismatch.Error(),
+		},
+		{
+			name: "transaction not traced",
+			traces: func(traces []txTrace) []txTrace {
+				traces[1].Result = nil
+				traces[1].Error = "execution timeout"
+				return traces
+			},
+			want: "trace transaction fail: execution timeout",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := flattenBlockTrace(traceBlock, transactions, c.traces(loadBlockTrace(t)))
+			if err == nil || err.Error() != c.want {
+				t.Fatalf("err = %v , want %s", err, c.want)
+			}
+		})
+	}
+}
+
+func TestFlattenBlockTraceWithoutTxHash(t *testing.T) {
+	//older nodes omit txHash , traces are matched by position only
+	traces := loadBlockTrace(t)
+	for i := range traces {
+		traces[i].TxHash = nil
+	}
+
+	res, err := flattenBlockTrace(traceBlock, traceTransactions(), traces)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 7 {
+		t.Fatalf("got %d calls , want 7", len(res))
+	}
+}
+
+func TestCreatedByNestedCalls(t *testing.T) {
+	res, err := flattenBlockTrace(traceBlock, traceTransactions(), loadBlockTrace(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//CREATE under reverted call and failed top level CREATE are dropped
+	created := createdByNestedCalls(res)
+	if len(created) != 1 || created[0].CallType != "CREATE2" || !reflect.DeepEqual(created[0].TraceAddress, []int{1, 0}) {
+		t.Fatalf("created = %+v , want the CREATE2 at [1 0]", created)
+	}
+
+	cases := []struct {
+		name  string
+		calls []domain.InternalTransaction
+		want  []int
+	}{
+		{
+			name: "revert of grand parent reaches nested create",
+			calls: []domain.InternalTransaction{
+				{TxHash: "0x1", TraceAddress: []int{}, Depth: 0, CallType: "CALL", ToAddress: "0xa"},
+				{TxHash: "0x1", TraceAddress: []int{0}, Depth: 1, CallType: "CALL", ToAddress: "0xb", Error: "execution reverted"},
+				{TxHash: "0x1", TraceAddress: []int{0, 0}, Depth: 2, CallType: "CALL", ToAddress: "0xc"},
+				{TxHash: "0x1", TraceAddress: []int{0, 0, 0}, Depth: 3, CallType: "CREATE", ToAddress: "0xd"},
+				{TxHash: "0x1", TraceAddress: []int{1}, Depth: 1, CallType: "CREATE", ToAddress: "0xe"},
+			},
+			want: []int{4},
+		},
+		{
+			name: "failed create and create without address",
+			calls: []domain.InternalTransaction{
+				{TxHash: "0x1", TraceAddress: []int{}, Depth: 0, CallType: "CALL", ToAddress: "0xa"},
+				{TxHash: "0x1", TraceAddress: []int{0}, Depth: 1, CallType: "CREATE2", ToAddress: "0xb", Error: "contract address collision"},
+				{TxHash: "0x1", TraceAddress: []int{1}, Depth: 1, CallType: "CREATE"},
+			},
+			want: nil,
+		},
+		{
+			name: "revert only applies within its transaction",
+			calls: []domain.InternalTransaction{
+				{TxHash: "0x1", TraceAddress: []int{}, Depth: 0, CallType: "CALL", ToAddress: "0xa", Error: "execution reverted"},
+				{TxHash: "0x1", TraceAddress: []int{0}, Depth: 1, CallType: "CREATE", ToAddress: "0xb"},
+				{TxHash: "0x2", TraceAddress: []int{}, Depth: 0, CallType: "CALL", ToAddress: "0xa"},
+				{TxHash: "0x2", TraceAddress: []int{0}, Depth: 1, CallType: "CREATE", ToAddress: "0xc"},
+			},
+			want: []int{3},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var got []int
+			created := createdByNestedCalls(c.calls)
+			for _, call := range created {
+				for i := range c.calls {
+					if reflect.DeepEqual(call, c.calls[i]) {
+						got = append(got, i)
+					}
+				}
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("created calls %v , want %v", got, c.want)
+			}
+		})
+	}
+}
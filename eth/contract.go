@@ -0,0 +1,129 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog/log"
+	"github.com/ryanCool/ethService/domain"
+	"math/big"
+	"strings"
+)
+
+// stateUnavailableErrors are error messages nodes return for state older than they keep
+var stateUnavailableErrors = []string{"missing trie node", "state is not available", "state not available", "historical state", "archive"}
+
+// newContract build contract creation with runtime bytecode fetched at creation block
+func (es *ethScan) newContract(ctx context.Context, address common.Address, creator string, position domain.InternalTransaction) (domain.Contract, error) {
+	code, atLatest, err := es.fetchCode(ctx, address, position.BlockNum)
+	if err != nil {
+		return domain.Contract{}, err
+	}
+
+	contract := domain.Contract{
+		Address:      address.String(),
+		Creator:      creator,
+		TxHash:       position.TxHash,
+		BlockHash:    position.BlockHash,
+		BlockNum:     position.BlockNum,
+		TxIndex:      position.TxIndex,
+		TraceIndex:   position.TraceIndex,
+		Bytecode:     code,
+		CodeAtLatest: atLatest,
+	}
+
+	//empty code of self destructed contracts would make all of them clones of each other
+	if len(code) > 0 {
+		contract.CodeHash = crypto.Keccak256Hash(code).String()
+	}
+
+	return contract, nil
+}
+
+// fetchCode fetch runtime bytecode at the end of block . Non archive node can not serve old state ,
+// latest code is used then and reported , which only differs when contract has self destructed since.
+// Any other error is returned so block is saved again later.
+func (es *ethScan) fetchCode(ctx context.Context, address common.Address, blockNum uint64) ([]byte, bool, error) {
+	code, err := es.rpcClient.CodeAt(ctx, address, new(big.Int).SetUint64(blockNum))
+	if err == nil {
+		return code, false, nil
+	}
+
+	if !isStateUnavailable(err) {
+		return nil, false, err
+	}
+
+	log.Debug().Err(err).Str("address", address.String()).Msg("state of creation block unavailable , fallback to latest code")
+	code, err = es.rpcClient.CodeAt(ctx, address, nil)
+	return code, true, err
+}
+
+// isStateUnavailable reports whether node failed because it pruned state of requested block
+func isStateUnavailable(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, s := range stateUnavailableErrors {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// saveCreatedContract save contract created by top level call of transaction , failed creation still reports an address
+func (es *ethScan) saveCreatedContract(ctx context.Context, receipt *types.Receipt, from common.Address) error {
+	if receipt.ContractAddress == (common.Address{}) || receipt.Status != types.ReceiptStatusSuccessful {
+		return nil
+	}
+
+	contract, err := es.newContract(ctx, receipt.ContractAddress, from.String(), domain.InternalTransaction{
+		TxHash:    receipt.TxHash.String(),
+		BlockHash: receipt.BlockHash.String(),
+		BlockNum:  receipt.BlockNumber.Uint64(),
+		TxIndex:   receipt.TransactionIndex,
+	})
+	if err != nil {
+		return err
+	}
+
+	return es.contractUcase.Save(ctx, []domain.Contract{contract})
+}
+
+// saveTracedContracts save contracts created by nested calls , top level creations are saved from receipt
+func (es *ethScan) saveTracedContracts(ctx context.Context, internalTransactions []domain.InternalTransaction) error {
+	var contracts []domain.Contract
+	for _, created := range createdByNestedCalls(internalTransactions) {
+		contract, err := es.newContract(ctx, common.HexToAddress(created.ToAddress), created.FromAddress, created)
+		if err != nil {
+			return err
+		}
+		contracts = append(contracts, contract)
+	}
+
+	return es.contractUcase.Save(ctx, contracts)
+}
+
+// createdByNestedCalls returns nested CREATE / CREATE2 calls whose effect persists ,
+// a call is reverted when it or any of its ancestors failed
+func createdByNestedCalls(internalTransactions []domain.InternalTransaction) []domain.InternalTransaction {
+	var res []domain.InternalTransaction
+	reverted := map[string]bool{}
+	for _, call := range internalTransactions {
+		key := fmt.Sprint(call.TxHash, call.TraceAddress)
+		reverted[key] = call.Error != ""
+		if call.Depth > 0 {
+			reverted[key] = reverted[key] || reverted[fmt.Sprint(call.TxHash, call.TraceAddress[:call.Depth-1])]
+		}
+
+		if reverted[key] || call.Depth == 0 || call.ToAddress == "" {
+			continue
+		}
+
+		if call.CallType == "CREATE" || call.CallType == "CREATE2" {
+			res = append(res, call)
+		}
+	}
+
+	return res
+}
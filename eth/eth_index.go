@@ -2,69 +2,168 @@ package eth
 
 import (
 	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/rs/zerolog/log"
 	"github.com/ryanCool/ethService/config"
 	"github.com/ryanCool/ethService/domain"
 	"math/big"
+	"sync"
+	"time"
 )
 
+// checkpointName is the name of sync checkpoint row owned by this scanner
+const checkpointName = "eth_scan"
+
+// saveBlockRetryNum is the times to try saving a block before leaving it to later repair
+const saveBlockRetryNum = 3
+
 var syncFromNBlock *big.Int
 var confirmedNum, scanWorkerNum, writeTransactionWorkerNum int
+var gapAuditInterval, pollInterval, maxReconnectBackoff, tokenRefreshInterval time.Duration
+var headSubscribeMode string
+var traceInternalTx bool
 
-//Initialize init cron job to subscribe new block event through websocket endpoint
+//Initialize backfill blocks from the sync checkpoint to latest , then follow new block event through websocket or polling
 func (es *ethScan) Initialize(ctx context.Context) {
 	confirmedNum = config.GetInt("CONFIRMED_BLOCK_NUM")
 	scanWorkerNum = config.GetInt("SCAN_WORK_NUM")
 	syncFromNBlock = config.GetBigInt("SYNC_BLOCK_FROM_N")
 	writeTransactionWorkerNum = config.GetInt("WRITE_TRANSACTION_WORK_NUM")
+	gapAuditInterval = time.Duration(config.GetInt("GAP_AUDIT_INTERVAL_SECS")) * time.Second
+	headSubscribeMode = config.GetString("HEAD_SUBSCRIBE_MODE")
+	pollInterval = time.Duration(config.GetInt("POLL_INTERVAL_SECS")) * time.Second
+	maxReconnectBackoff = time.Duration(config.GetInt("WS_RECONNECT_MAX_BACKOFF_SECS")) * time.Second
+	traceInternalTx = config.GetBool("TRACE_INTERNAL_TX")
+	tokenRefreshInterval = time.Duration(config.GetInt("TOKEN_METADATA_REFRESH_SECS")) * time.Second
+	validateIntervals()
+	validateHeadSubscribeMode()
+
+	next, err := es.loadCheckpoint(ctx)
+	if err != nil {
+		panic(err)
+	}
+	log.Info().Uint64("block_num", next).Msg("resume sync from block")
+	es.cursor = newSyncCursor(next, es.saveCheckpoint)
+
+	if err = es.loadWatchlist(ctx); err != nil {
+		panic(err)
+	}
+
+	if err = es.loadAbis(ctx); err != nil {
+		panic(err)
+	}
 
-	go es.subscribeNewBlock(ctx)
-	//go es.scanToLatest(ctx)
+	go func() {
+		es.scanToLatest(ctx)
+		go es.runCatchUp(ctx)
+		es.subscribeNewBlock(ctx)
+	}()
+	go es.auditGaps(ctx)
+	go es.trackFinality(ctx)
+	go es.applyBalances(ctx)
+	go es.refreshWatchlist(ctx)
+	go es.refreshAbis(ctx)
+	go es.refreshTokens(ctx)
+}
+
+//validateIntervals reject non positive periods before any loop starts , a ticker panics on them deep inside a goroutine
+func validateIntervals() {
+	intervals := []struct {
+		key      string
+		interval time.Duration
+	}{
+		{"GAP_AUDIT_INTERVAL_SECS", gapAuditInterval},
+		{"POLL_INTERVAL_SECS", pollInterval},
+		{"WS_RECONNECT_MAX_BACKOFF_SECS", maxReconnectBackoff},
+	}
+
+	for _, item := range intervals {
+		if item.interval <= 0 {
+			panic("config invalid " + item.key + " : must be at least 1 second")
+		}
+	}
 }
 
 type ethScan struct {
 	rpcClient        *ethclient.Client
-	wsClient         *ethclient.Client
+	rpcRawClient     *rpc.Client
+	wsClient         *rpc.Client
+	dialWs           func(ctx context.Context) (*rpc.Client, error)
 	transactionUcase domain.TransactionUseCase
 	blockUCase       domain.BlockUseCase
+	checkpointUcase  domain.CheckpointUseCase
+	tokenUcase       domain.TokenUseCase
+	balanceUcase     domain.BalanceUseCase
+	watchlistUcase   domain.WatchlistUseCase
+	abiUcase         domain.AbiUseCase
+	contractUcase    domain.ContractUseCase
+	cursor           *syncCursor
+	reorgMu          *sync.Mutex
+	ledgerMu         *sync.Mutex
+	lastHeadNum      uint64
+
+	// catchUp requests a backfill to latest , pending requests are merged into one
+	catchUp chan struct{}
+
+	// watched is the watchlist cache , replaced as a whole on reload
+	watched map[common.Address]bool
+	watchMu *sync.RWMutex
+
+	// abis is the registered abi cache by contract address , replaced as a whole on reload
+	abis  map[common.Address]*abi.ABI
+	abiMu *sync.RWMutex
 }
 
-func NewEthScan(rpcClient *ethclient.Client, wsClient *ethclient.Client, transactionUcase domain.TransactionUseCase, blockUcase domain.BlockUseCase) ethScan {
+func NewEthScan(rpcClient *ethclient.Client, rpcRawClient *rpc.Client, dialWs func(ctx context.Context) (*rpc.Client, error), transactionUcase domain.TransactionUseCase, blockUcase domain.BlockUseCase, checkpointUcase domain.CheckpointUseCase, tokenUcase domain.TokenUseCase, balanceUcase domain.BalanceUseCase, watchlistUcase domain.WatchlistUseCase, abiUcase domain.AbiUseCase, contractUcase domain.ContractUseCase) ethScan {
 	return ethScan{
 		rpcClient:        rpcClient,
-		wsClient:         wsClient,
+		rpcRawClient:     rpcRawClient,
+		dialWs:           dialWs,
 		transactionUcase: transactionUcase,
 		blockUCase:       blockUcase,
+		checkpointUcase:  checkpointUcase,
+		tokenUcase:       tokenUcase,
+		balanceUcase:     balanceUcase,
+		watchlistUcase:   watchlistUcase,
+		abiUcase:         abiUcase,
+		contractUcase:    contractUcase,
+		reorgMu:          &sync.Mutex{},
+		ledgerMu:         &sync.Mutex{},
+		watchMu:          &sync.RWMutex{},
+		abiMu:            &sync.RWMutex{},
+		catchUp:          make(chan struct{}, 1),
 	}
 }
 
-func (es *ethScan) subscribeNewBlock(ctx context.Context) {
-	headers := make(chan *types.Header)
-	sub, err := es.wsClient.SubscribeNewHead(ctx, headers)
+// loadCheckpoint returns the first block to index , SYNC_BLOCK_FROM_N if scanner never ran before
+func (es *ethScan) loadCheckpoint(ctx context.Context) (uint64, error) {
+	checkpoint, err := es.checkpointUcase.Get(ctx, checkpointName)
+	if err == domain.ErrCheckpointNotExist {
+		return syncFromNBlock.Uint64(), nil
+	}
+
 	if err != nil {
-		log.Error().Err(err).Msg("subscribe new block fail")
-	}
-
-	for {
-		select {
-		case err := <-sub.Err():
-			log.Error().Err(err).Msg("receive subsc")
-		case header := <-headers: //get new block event
-			log.Info().Uint64("block_num", header.Number.Uint64()).Msg("get new block")
-			go es.setNewBlock(ctx, header.Number.Uint64())
-			go es.setOldBlock(ctx, header.Number.Uint64()-uint64(confirmedNum))
-		case <-ctx.Done():
-			log.Print("break subscribe loop")
-			return
-		}
+		return 0, err
 	}
+
+	return checkpoint.BlockNum + 1, nil
 }
 
-func (es *ethScan) FetchBlock(ctx context.Context, blockNum *big.Int, stable bool) (*domain.BlockDb, types.Transactions, error) {
-	block, err := es.rpcClient.BlockByNumber(context.Background(), blockNum)
+func (es *ethScan) saveCheckpoint(blockNum uint64, blockHash string) error {
+	return es.checkpointUcase.Save(context.Background(), &domain.SyncCheckpoint{
+		Name:      checkpointName,
+		BlockNum:  blockNum,
+		BlockHash: blockHash,
+	})
+}
+
+func (es *ethScan) FetchBlock(ctx context.Context, blockNum *big.Int, stable bool) (*domain.BlockDb, *types.Block, error) {
+	block, head, err := es.fetchBlock(ctx, blockNum)
 	if err != nil {
 		log.Err(err).Msg("fetch block fail in block by number")
 		return nil, nil, err
@@ -72,31 +171,80 @@ func (es *ethScan) FetchBlock(ctx context.Context, blockNum *big.Int, stable boo
 
 	log.Info().Msg("fetch block =" + block.Number().String())
 
-	return wrapBlockDb(block, stable), block.Transactions(), nil
+	blockDb := wrapBlockDb(block, head, stable)
+	setRawHeader(blockDb, head)
+
+	return blockDb, block, nil
 }
 
-func wrapBlockDb(block *types.Block, stable bool) *domain.BlockDb {
-	return &domain.BlockDb{
-		BlockNum:   block.NumberU64(),
-		BlockHash:  block.Hash().String(),
-		BlockTime:  block.Time(),
-		ParentHash: block.ParentHash().String(),
-		Stable:     stable,
+//wrapBlockDb build block row , block and uncle hashes are the ones node reported in head
+func wrapBlockDb(block *types.Block, head *rawHeader, stable bool) *domain.BlockDb {
+	blockDb := &domain.BlockDb{
+		BlockNum:         block.NumberU64(),
+		BlockHash:        head.Hash.String(),
+		BlockTime:        block.Time(),
+		ParentHash:       block.ParentHash().String(),
+		TxCount:          len(block.Transactions()),
+		Stable:           stable,
+		Miner:            block.Coinbase().String(),
+		GasLimit:         block.GasLimit(),
+		GasUsed:          block.GasUsed(),
+		Difficulty:       block.Difficulty().String(),
+		ExtraData:        block.Extra(),
+		StateRoot:        block.Root().String(),
+		TransactionsRoot: block.TxHash().String(),
+		ReceiptsRoot:     block.ReceiptHash().String(),
+		Sha3Uncles:       block.UncleHash().String(),
+		Nonce:            hexutil.Encode(block.Header().Nonce[:]),
+		MixHash:          block.MixDigest().String(),
+		LogsBloom:        block.Bloom().Bytes(),
+	}
+
+	if block.BaseFee() != nil {
+		blockDb.BaseFeePerGas = block.BaseFee().String()
 	}
+
+	for i, uncle := range block.Uncles() {
+		blockDb.Uncles = append(blockDb.Uncles, domain.Uncle{
+			BlockHash:  blockDb.BlockHash,
+			BlockNum:   blockDb.BlockNum,
+			UncleIndex: i,
+			UncleHash:  head.UncleHashes[i].String(),
+			UncleNum:   uncle.Number.Uint64(),
+			Miner:      uncle.Coinbase.String(),
+			Header:     head.UncleHeaders[i],
+		})
+	}
+
+	return blockDb
 }
 
-func (es *ethScan) setNewBlock(ctx context.Context, blockNum uint64) {
-	err := es.saveBlock(ctx, blockNum, false)
+//setNewBlock save new block , blocks between sync cursor and new block are saved first
+func (es *ethScan) setNewBlock(ctx context.Context, header *nodeHeader) {
+	blockNum := header.Number.Uint64()
+	if from := es.cursor.schedule(blockNum); from < blockNum {
+		log.Info().Uint64("from", from).Uint64("to", blockNum-1).Msg("catch up missing blocks")
+		es.indexRange(ctx, from, blockNum-1, blockNum)
+	}
+
+	//parent hash mismatch means stored blocks were orphaned , index replaced ones again
+	forkNum, err := es.handleReorg(ctx, header)
 	if err != nil {
-		log.Err(err).Msg("set new block fail - saveBlock")
+		log.Err(err).Uint64("block_num", blockNum).Msg("handle reorg fail")
 	}
+	if forkNum < blockNum {
+		es.indexRange(ctx, forkNum, blockNum-1, blockNum)
+	}
+
+	es.indexBlock(ctx, blockNum, false)
 }
 
 //setOldBlock set old block to stable
 func (es *ethScan) setOldBlock(ctx context.Context, oldBlockNum uint64) {
-	b, err := es.rpcClient.BlockByNumber(ctx, big.NewInt(int64(oldBlockNum)))
+	header, err := es.fetchHeader(ctx, hexutil.EncodeUint64(oldBlockNum))
 	if err != nil {
 		log.Error().Err(err).Msg("set block stable fail - Get by number")
+		return
 	}
 
 	oldBlock, err := es.blockUCase.GetByNumber(ctx, oldBlockNum)
@@ -106,11 +254,8 @@ func (es *ethScan) setOldBlock(ctx context.Context, oldBlockNum uint64) {
 
 	//replace if old one is unstable by checking block hash
 	//if block not exist or hash not equal , new one
-	if oldBlock == nil || oldBlock.BlockHash != b.Hash().String() {
-		err = es.saveBlock(ctx, oldBlockNum, true)
-		if err != nil {
-			log.Error().Err(err).Msg("save block fail in set old block")
-		}
+	if oldBlock == nil || oldBlock.BlockHash != header.Hash.String() {
+		es.indexBlock(ctx, oldBlockNum, true)
 		return
 	}
 
@@ -122,84 +267,143 @@ func (es *ethScan) setOldBlock(ctx context.Context, oldBlockNum uint64) {
 	return
 }
 
-func (es *ethScan) saveBlock(ctx context.Context, blockNum uint64, stable bool) error {
+//saveBlock save block with all its transactions and receipts , return when block is fully indexed
+func (es *ethScan) saveBlock(ctx context.Context, blockNum uint64, stable bool) (*domain.BlockDb, error) {
 	//check exist block in db
 	b, err := es.blockUCase.GetByNumber(ctx, blockNum)
 	if err != nil && err != domain.ErrBlockNotExist {
 		log.Err(err).Msg("get block from blockRepo fail")
-		return err
+		return nil, err
 	}
 
 	//block exist , and is not stable block  . Don't need to replace
 	if b != nil && !stable {
-		return nil
+		return &b.BlockDb, nil
 	} else if b != nil && stable { //exist old , we should replace by new fetch one . Delete first
-		err = es.blockUCase.DeleteByNum(ctx, blockNum)
+		err = es.deleteBlock(ctx, blockNum)
 		if err != nil {
 			log.Err(err).Msg("delete block by num fail")
-			return err
+			return nil, err
 		}
 	}
 
 	//block not exist in db
-	block, transactions, err := es.FetchBlock(ctx, big.NewInt(int64(blockNum)), stable)
+	block, rawBlock, err := es.FetchBlock(ctx, big.NewInt(int64(blockNum)), stable)
 	if err != nil {
 		log.Err(err).Msg("Fetch block fail when sync to latest block")
-		return err
+		return nil, err
 	}
 
 	err = es.blockUCase.Create(ctx, block)
 	if err != nil {
 		log.Err(err).Msg("New block fail when sync to latest block")
-		return err
+		return nil, err
 	}
 
+	var wg sync.WaitGroup
+	var saveErr error
+	var errOnce sync.Once
 	c := make(chan bool, writeTransactionWorkerNum)
-	for _, transaction := range transactions {
+	for i, transaction := range rawBlock.Transactions() {
 		c <- true
-		go func(transaction types.Transaction) {
-			err = es.Save(ctx, block.BlockHash, &transaction)
-			if err != nil {
+		wg.Add(1)
+		go func(txIndex int, transaction types.Transaction) {
+			defer wg.Done()
+			if err := es.Save(ctx, block, rawBlock, txIndex, &transaction); err != nil {
 				log.Err(err).Msg("save transaction fail when sync to latest block")
+				errOnce.Do(func() { saveErr = err })
 			}
 			<-c
-		}(*transaction)
+		}(i, *transaction)
 	}
-	return nil
+	wg.Wait()
+
+	//internal transactions reference saved transactions , trace after all of them are written
+	if saveErr == nil && traceInternalTx {
+		saveErr = es.saveInternalTransactions(ctx, block, rawBlock)
+	}
+
+	if saveErr == nil {
+		saveErr = es.saveSnapshots(ctx, block, rawBlock)
+	}
+
+	//drop partially written block , so next attempt starts from clean state
+	if saveErr != nil {
+		if err := es.deleteBlock(ctx, blockNum); err != nil {
+			log.Err(err).Msg("delete partially written block fail")
+		}
+		return nil, saveErr
+	}
+
+	return block, nil
 }
 
-//ScanToLatest scan blocks from n to latest , and store to db
-func (es *ethScan) scanToLatest(ctx context.Context) {
-	header, err := es.rpcClient.HeaderByNumber(context.Background(), nil)
+//indexBlock save block with retry , and move sync cursor forward once it is fully indexed
+func (es *ethScan) indexBlock(ctx context.Context, blockNum uint64, stable bool) {
+	//another worker is writing this block
+	if !es.cursor.begin(blockNum) {
+		return
+	}
+	defer es.cursor.end(blockNum)
+
+	var block *domain.BlockDb
+	var err error
+	for i := 0; i < saveBlockRetryNum; i++ {
+		if block, err = es.saveBlock(ctx, blockNum, stable); err == nil {
+			break
+		}
+	}
+
 	if err != nil {
-		log.Error().Err(err)
+		log.Err(err).Uint64("block_num", blockNum).Msg("save block fail")
+		es.cursor.fail(blockNum)
+		return
 	}
 
-	latestNum := header.Number.Uint64()
-	log.Info().Uint64("latestNum", latestNum).Msg("latest block num=")
-	targetBlockNum := syncFromNBlock
+	if err = es.cursor.complete(blockNum, block.BlockHash); err != nil {
+		log.Err(err).Uint64("block_num", blockNum).Msg("save sync checkpoint fail")
+	}
+}
 
-	//use esffer channel to implement a worker pool with config number
+//indexRange index blocks from n to m with worker pool , and wait all of them done
+func (es *ethScan) indexRange(ctx context.Context, from uint64, to uint64, latestNum uint64) {
+	//use buffer channel to implement a worker pool with config number
+	var wg sync.WaitGroup
 	c := make(chan bool, scanWorkerNum)
-	for targetBlockNum.Uint64() <= latestNum {
+	for targetBlockNum := from; targetBlockNum <= to && ctx.Err() == nil; targetBlockNum++ {
 		c <- true
-		go func(latestNum uint64, targetBlockNum uint64) {
-			stable := true
-			if targetBlockNum > latestNum-uint64(confirmedNum) {
-				stable = false
-			}
-
-			err = es.saveBlock(ctx, targetBlockNum, stable)
-			if err != nil {
-				log.Err(err).Msg("save block fail")
-			}
+		wg.Add(1)
+		go func(targetBlockNum uint64) {
+			defer wg.Done()
+			stable := targetBlockNum+uint64(confirmedNum) <= latestNum
+			es.indexBlock(ctx, targetBlockNum, stable)
 
 			//job done , and release worker
 			<-c
-		}(latestNum, targetBlockNum.Uint64())
-		targetBlockNum = targetBlockNum.Add(targetBlockNum, big.NewInt(1))
+		}(targetBlockNum)
 	}
+	wg.Wait()
+}
+
+//scanToLatest scan blocks from sync cursor to latest , and store to db
+func (es *ethScan) scanToLatest(ctx context.Context) {
+	//chain keeps growing while we backfill , repeat until no new block left
+	for ctx.Err() == nil {
+		header, err := es.rpcClient.HeaderByNumber(ctx, nil)
+		if err != nil {
+			log.Error().Err(err).Msg("get latest header fail")
+			return
+		}
 
+		latestNum := header.Number.Uint64()
+		from := es.cursor.schedule(latestNum)
+		if from > latestNum {
+			return
+		}
+
+		log.Info().Uint64("from", from).Uint64("latestNum", latestNum).Msg("scan to latest block")
+		es.indexRange(ctx, from, latestNum, latestNum)
+	}
 }
 
 //setBlockStable set old block to stable status
@@ -207,7 +411,8 @@ func (es *ethScan) setBlockStable(ctx context.Context, blockNum uint64, stable b
 	return es.blockUCase.SetStable(ctx, blockNum, stable)
 }
 
-func (es *ethScan) saveReceipt(ctx context.Context, txHash common.Hash) error {
+func (es *ethScan) saveReceipt(ctx context.Context, transaction *types.Transaction, from common.Address, baseFee *big.Int) error {
+	txHash := transaction.Hash()
 	receipt, err := es.rpcClient.TransactionReceipt(context.Background(), txHash)
 	if err != nil {
 		log.Err(err).Msg("save receipt fail when get receipt through rpc client")
@@ -217,24 +422,85 @@ func (es *ethScan) saveReceipt(ctx context.Context, txHash common.Hash) error {
 	logs := []domain.TransactionLog{}
 	for _, l := range receipt.Logs {
 		tl := domain.TransactionLog{
-			TxHash:   txHash.String(),
-			LogIndex: int(l.Index),
-			LogData:  l.Data,
+			TxHash:    txHash.String(),
+			BlockHash: l.BlockHash.String(),
+			BlockNum:  l.BlockNumber,
+			TxIndex:   l.TxIndex,
+			LogIndex:  int(l.Index),
+			Address:   l.Address.String(),
+			LogData:   l.Data,
+		}
+		setLogTopics(&tl, l.Topics)
+		if parsed := es.getAbi(l.Address); parsed != nil {
+			tl.Decoded = decodeLog(parsed, l)
 		}
 		logs = append(logs, tl)
 	}
 
-	if len(logs) > 0 {
-		err = es.transactionUcase.SaveReceiptAndLogs(ctx, txHash.String(), logs)
-		if err != nil {
-			return err
-		}
+	//token transfers are written before receipt , so a failure leaves the transaction incomplete for gap auditor
+	err = es.tokenUcase.SaveTransfers(ctx, decodeTokenTransfers(receipt.Logs))
+	if err != nil {
+		return err
+	}
+
+	err = es.tokenUcase.SaveNftTransfers(ctx, decodeNftTransfers(receipt.Logs))
+	if err != nil {
+		return err
+	}
+
+	err = es.saveCreatedContract(ctx, receipt, from)
+	if err != nil {
+		return err
+	}
+
+	//receipt row is written even without logs , gap auditor counts it to tell a transaction is complete
+	err = es.transactionUcase.SaveReceiptAndLogs(ctx, wrapReceipt(receipt, transaction, baseFee), logs)
+	if err != nil {
+		return err
 	}
 
 	return nil
 }
 
-func (es *ethScan) Save(ctx context.Context, blockHash string, transaction *types.Transaction) error {
+//setLogTopics fill topic columns , anonymous events have less than 4 topics
+func setLogTopics(tl *domain.TransactionLog, topics []common.Hash) {
+	columns := []*string{&tl.Topic0, &tl.Topic1, &tl.Topic2, &tl.Topic3}
+	for i, topic := range topics {
+		if i >= len(columns) {
+			break
+		}
+		*columns[i] = topic.String()
+	}
+}
+
+func wrapReceipt(receipt *types.Receipt, transaction *types.Transaction, baseFee *big.Int) *domain.Receipt {
+	r := &domain.Receipt{
+		TxHash:            receipt.TxHash.String(),
+		TxType:            receipt.Type,
+		Status:            receipt.Status,
+		GasUsed:           receipt.GasUsed,
+		CumulativeGasUsed: receipt.CumulativeGasUsed,
+		EffectiveGasPrice: effectiveGasPrice(transaction, baseFee).String(),
+		LogsBloom:         receipt.Bloom.Bytes(),
+	}
+
+	//contract address is only set for contract creation
+	if receipt.ContractAddress != (common.Address{}) {
+		r.ContractAddress = receipt.ContractAddress.String()
+	}
+
+	return r
+}
+
+//effectiveGasPrice price actually paid per gas , base fee plus tip after london , gas price before
+func effectiveGasPrice(transaction *types.Transaction, baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return transaction.GasPrice()
+	}
+	return new(big.Int).Add(baseFee, transaction.EffectiveGasTipValue(baseFee))
+}
+
+func (es *ethScan) Save(ctx context.Context, block *domain.BlockDb, rawBlock *types.Block, txIndex int, transaction *types.Transaction) error {
 	from, err := types.Sender(types.LatestSignerForChainID(transaction.ChainId()), transaction)
 	if err != nil {
 		return err
@@ -244,25 +510,58 @@ func (es *ethScan) Save(ctx context.Context, blockHash string, transaction *type
 	if to == nil {
 		to = &common.Address{}
 	}
-	err = es.transactionUcase.Create(ctx, &domain.Transaction{
-		BlockHash: blockHash,
+	v, r, s := transaction.RawSignatureValues()
+	tx := &domain.Transaction{
+		BlockHash: block.BlockHash,
+		BlockNum:  block.BlockNum,
+		TxIndex:   uint(txIndex),
 		TxHash:    transaction.Hash().String(),
+		TxType:    transaction.Type(),
 		TxFrom:    from.String(),
 		TxTo:      to.String(),
 		Nonce:     transaction.Nonce(),
 		TxData:    transaction.Data(),
 		TxValue:   transaction.Value().String(),
-	})
+		ChainID:   transaction.ChainId().String(),
+		Gas:       transaction.Gas(),
+		GasPrice:  transaction.GasPrice().String(),
+		V:         v.String(),
+		R:         r.String(),
+		S:         s.String(),
+	}
+
+	if transaction.Type() == types.DynamicFeeTxType {
+		tx.MaxFeePerGas = transaction.GasFeeCap().String()
+		tx.MaxPriorityFeePerGas = transaction.GasTipCap().String()
+	}
+
+	if parsed := es.getAbi(*to); parsed != nil {
+		tx.DecodedInput = decodeInput(parsed, transaction.Data())
+	}
+
+	for i, tuple := range transaction.AccessList() {
+		entry := domain.AccessListEntry{
+			TxHash:      tx.TxHash,
+			EntryIndex:  i,
+			Address:     tuple.Address.String(),
+			StorageKeys: []string{},
+		}
+		for _, key := range tuple.StorageKeys {
+			entry.StorageKeys = append(entry.StorageKeys, key.String())
+		}
+		tx.AccessList = append(tx.AccessList, entry)
+	}
+
+	err = es.transactionUcase.Create(ctx, tx)
 	if err != nil {
 		return err
 	}
 
-	go func() {
-		err = es.saveReceipt(ctx, transaction.Hash())
-		if err != nil {
-			log.Err(err).Msg("save receipt fail")
-		}
-	}()
+	err = es.saveReceipt(ctx, transaction, from, rawBlock.BaseFee())
+	if err != nil {
+		log.Err(err).Msg("save receipt fail")
+		return err
+	}
 
 	return nil
 }
@@ -0,0 +1,48 @@
+package eth
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"github.com/ryanCool/ethService/domain"
+	"time"
+)
+
+// trackFinality periodically record node safe and finalized block as checkpoints , api resolves block tags with them
+func (es *ethScan) trackFinality(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			es.saveFinalityCheckpoint(ctx, domain.CheckpointSafe)
+			es.saveFinalityCheckpoint(ctx, domain.CheckpointFinalized)
+		case <-ctx.Done():
+			log.Print("break finality loop")
+			return
+		}
+	}
+}
+
+// saveFinalityCheckpoint fetch block of tag safe or finalized and save it as checkpoint of the same name
+func (es *ethScan) saveFinalityCheckpoint(ctx context.Context, tag string) {
+	var header *nodeHeader
+	if err := es.rpcRawClient.CallContext(ctx, &header, "eth_getBlockByNumber", tag, false); err != nil {
+		//pre-merge chains do not know the tag
+		log.Debug().Err(err).Str("tag", tag).Msg("get block by tag fail")
+		return
+	}
+
+	if header == nil {
+		return
+	}
+
+	err := es.checkpointUcase.Save(ctx, &domain.SyncCheckpoint{
+		Name:      tag,
+		BlockNum:  header.Number.Uint64(),
+		BlockHash: header.Hash.String(),
+	})
+	if err != nil {
+		log.Err(err).Str("tag", tag).Msg("save finality checkpoint fail")
+	}
+}
@@ -0,0 +1,144 @@
+package eth
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"github.com/ryanCool/ethService/domain"
+	"time"
+)
+
+// gapAuditCheckpointName is the name of checkpoint row holding the last block checked by gap auditor , its hash is left empty
+const gapAuditCheckpointName = "gap_audit"
+
+// gapRepairLimit is the max number of gaps and incomplete blocks handled in one audit round
+const gapRepairLimit = 100
+
+// gapRepairBlockNum is the max number of missing blocks refetched in one audit round
+const gapRepairBlockNum = 1000
+
+// auditGaps periodically look for missing or partially written blocks in db and save them again
+func (es *ethScan) auditGaps(ctx context.Context) {
+	ticker := time.NewTicker(gapAuditInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			es.repairGaps(ctx)
+		case <-ctx.Done():
+			log.Print("break gap audit loop")
+			return
+		}
+	}
+}
+
+// repairGaps run one audit round , blocks given up by workers are retried first since sync cursor is held behind them
+func (es *ethScan) repairGaps(ctx context.Context) {
+	latestNum := es.cursor.latest()
+	for _, blockNum := range es.cursor.takeFailed() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Info().Uint64("block_num", blockNum).Msg("retry failed block")
+		es.indexBlock(ctx, blockNum, blockNum+uint64(confirmedNum) <= latestNum)
+	}
+
+	from, err := es.gapAuditFrom(ctx, latestNum)
+	if err != nil {
+		log.Err(err).Msg("get gap audit checkpoint fail")
+		return
+	}
+
+	report, err := es.blockUCase.GapReport(ctx, from, latestNum, gapRepairLimit)
+	if err != nil {
+		log.Err(err).Msg("get gap report fail")
+		return
+	}
+
+	//nothing stored in window
+	if report.ToBlock < report.FromBlock {
+		return
+	}
+
+	if report.MissingBlockNum == 0 && len(report.IncompleteBlocks) == 0 {
+		es.saveGapAuditCheckpoint(ctx, report.ToBlock)
+		return
+	}
+
+	log.Warn().Uint64("missing_block_num", report.MissingBlockNum).Int("incomplete_block_num", len(report.IncompleteBlocks)).
+		Msg("found gaps in indexed blocks")
+
+	//window is checked again next round when holes are left over , cursor only moves past a window repaired whole
+	done := len(report.MissingRanges) < gapRepairLimit && len(report.IncompleteBlocks) < gapRepairLimit
+
+	budget := uint64(gapRepairBlockNum)
+	for _, gap := range report.MissingRanges {
+		if budget == 0 || ctx.Err() != nil {
+			done = false
+			break
+		}
+
+		to := gap.ToBlock
+		if to-gap.FromBlock+1 > budget {
+			to = gap.FromBlock + budget - 1
+			done = false
+		}
+		budget -= to - gap.FromBlock + 1
+
+		log.Info().Uint64("from", gap.FromBlock).Uint64("to", to).Msg("repair missing blocks")
+		es.indexRange(ctx, gap.FromBlock, to, latestNum)
+	}
+
+	for _, blockNum := range report.IncompleteBlocks {
+		if ctx.Err() != nil {
+			done = false
+			break
+		}
+
+		//block is being written by another worker , not a real hole
+		if !es.cursor.begin(blockNum) {
+			continue
+		}
+		err = es.deleteBlock(ctx, blockNum)
+		es.cursor.end(blockNum)
+		if err != nil {
+			log.Err(err).Uint64("block_num", blockNum).Msg("delete incomplete block fail")
+			done = false
+			continue
+		}
+
+		log.Info().Uint64("block_num", blockNum).Msg("repair incomplete block")
+		es.indexBlock(ctx, blockNum, blockNum+uint64(confirmedNum) <= latestNum)
+	}
+
+	if done {
+		es.saveGapAuditCheckpoint(ctx, report.ToBlock)
+	}
+}
+
+// gapAuditFrom returns the first block of next audit window , the block after audit cursor .
+// Cursor starts over from syncFromNBlock once it reaches latestNum.
+func (es *ethScan) gapAuditFrom(ctx context.Context, latestNum uint64) (uint64, error) {
+	from := syncFromNBlock.Uint64()
+
+	audited, err := es.checkpointUcase.Get(ctx, gapAuditCheckpointName)
+	if err == domain.ErrCheckpointNotExist {
+		return from, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if audited.BlockNum >= from && audited.BlockNum < latestNum {
+		from = audited.BlockNum + 1
+	}
+	return from, nil
+}
+
+func (es *ethScan) saveGapAuditCheckpoint(ctx context.Context, blockNum uint64) {
+	err := es.checkpointUcase.Save(ctx, &domain.SyncCheckpoint{Name: gapAuditCheckpointName, BlockNum: blockNum})
+	if err != nil {
+		log.Err(err).Uint64("block_num", blockNum).Msg("save gap audit checkpoint fail")
+	}
+}
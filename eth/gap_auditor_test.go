@@ -0,0 +1,107 @@
+package eth
+
+import (
+	"context"
+	"github.com/ryanCool/ethService/domain"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// intactBlocks is block use case without holes , recording windows of gap reports
+type intactBlocks struct {
+	domain.BlockUseCase
+	windows []domain.BlockGap
+}
+
+func (b *intactBlocks) GapReport(ctx context.Context, fromBlock uint64, toBlock uint64, limit int) (*domain.GapReport, error) {
+	if end := fromBlock + domain.GapReportMaxBlockNum - 1; toBlock == 0 || toBlock > end {
+		toBlock = end
+	}
+	b.windows = append(b.windows, domain.BlockGap{FromBlock: fromBlock, ToBlock: toBlock})
+	return &domain.GapReport{FromBlock: fromBlock, ToBlock: toBlock}, nil
+}
+
+// memCheckpoints is checkpoint use case kept in memory
+type memCheckpoints map[string]domain.SyncCheckpoint
+
+func (m memCheckpoints) Get(ctx context.Context, name string) (*domain.SyncCheckpoint, error) {
+	checkpoint, exist := m[name]
+	if !exist {
+		return nil, domain.ErrCheckpointNotExist
+	}
+	return &checkpoint, nil
+}
+
+func (m memCheckpoints) Save(ctx context.Context, checkpoint *domain.SyncCheckpoint) error {
+	m[checkpoint.Name] = *checkpoint
+	return nil
+}
+
+func TestRepairGapsAuditCursor(t *testing.T) {
+	prev := syncFromNBlock
+	syncFromNBlock = big.NewInt(100)
+	defer func() { syncFromNBlock = prev }()
+
+	blocks := &intactBlocks{}
+	checkpoints := memCheckpoints{}
+	cursor, _ := newTestCursor(25101)
+	es := &ethScan{blockUCase: blocks, checkpointUcase: checkpoints, cursor: cursor}
+
+	for i := 0; i < 4; i++ {
+		es.repairGaps(context.Background())
+	}
+
+	want := []domain.BlockGap{
+		{FromBlock: 100, ToBlock: 10099},
+		{FromBlock: 10100, ToBlock: 20099},
+		{FromBlock: 20100, ToBlock: 25100},
+		{FromBlock: 100, ToBlock: 10099},
+	}
+	if !reflect.DeepEqual(blocks.windows, want) {
+		t.Fatalf("audit windows %v , want %v", blocks.windows, want)
+	}
+
+	if got := checkpoints[gapAuditCheckpointName].BlockNum; got != 10099 {
+		t.Fatalf("audit cursor %d , want 10099", got)
+	}
+}
+
+func TestGapAuditFrom(t *testing.T) {
+	prev := syncFromNBlock
+	syncFromNBlock = big.NewInt(100)
+	defer func() { syncFromNBlock = prev }()
+
+	cases := []struct {
+		name    string
+		audited *uint64
+		want    uint64
+	}{
+		{"no cursor", nil, 100},
+		{"after cursor", newUint64(500), 501},
+		{"cursor below sync start", newUint64(50), 100},
+		{"cursor at latest", newUint64(1000), 100},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			checkpoints := memCheckpoints{}
+			if c.audited != nil {
+				checkpoints[gapAuditCheckpointName] = domain.SyncCheckpoint{Name: gapAuditCheckpointName, BlockNum: *c.audited}
+			}
+			es := &ethScan{checkpointUcase: checkpoints}
+
+			from, err := es.gapAuditFrom(context.Background(), 1000)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if from != c.want {
+				t.Fatalf("from %d , want %d", from, c.want)
+			}
+		})
+	}
+}
+
+func newUint64(v uint64) *uint64 {
+	return &v
+}
@@ -0,0 +1,184 @@
+package eth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+	"github.com/ryanCool/ethService/config"
+	"strings"
+	"time"
+)
+
+// minReconnectBackoff is the first wait before redialing a dead websocket subscription
+const minReconnectBackoff = time.Second
+
+const (
+	subscribeModeWs   = "ws"
+	subscribeModePoll = "poll"
+)
+
+// validateHeadSubscribeMode reject unknown mode and ws mode without websocket endpoint before any loop starts
+func validateHeadSubscribeMode() {
+	switch headSubscribeMode {
+	case subscribeModePoll:
+	case subscribeModeWs:
+		if ws, _ := config.LookupString("WS_ENDPOINT"); strings.TrimSpace(ws) == "" {
+			panic("config WS_ENDPOINT is required in ws head subscribe mode")
+		}
+	default:
+		panic("invalid head subscribe mode " + headSubscribeMode)
+	}
+}
+
+// subscribeNewBlock follow new block event , by websocket subscription or polling latest header
+func (es *ethScan) subscribeNewBlock(ctx context.Context) {
+	switch headSubscribeMode {
+	case subscribeModePoll:
+		es.pollNewBlock(ctx)
+	case subscribeModeWs:
+		es.superviseSubscription(ctx)
+	default:
+		panic("invalid head subscribe mode " + headSubscribeMode)
+	}
+}
+
+// superviseSubscription keeps websocket subscription alive , redial with exponential backoff when it dies
+func (es *ethScan) superviseSubscription(ctx context.Context) {
+	backoff := minReconnectBackoff
+	for {
+		received, err := es.runSubscription(ctx)
+		if ctx.Err() != nil {
+			log.Print("break subscribe loop")
+			return
+		}
+
+		//subscription worked for a while , the next failure starts from short wait again
+		if received {
+			backoff = minReconnectBackoff
+		}
+		log.Error().Err(err).Dur("backoff", backoff).Msg("subscription lost , resubscribe later")
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			log.Print("break subscribe loop")
+			return
+		}
+
+		backoff *= 2
+		if backoff > maxReconnectBackoff {
+			backoff = maxReconnectBackoff
+		}
+	}
+}
+
+// runSubscription subscribe new head and dispatch headers until subscription fails .
+// Reports whether any header was received.
+func (es *ethScan) runSubscription(ctx context.Context) (bool, error) {
+	if es.wsClient == nil {
+		c, err := es.dialWs(ctx)
+		if err != nil {
+			return false, err
+		}
+		es.wsClient = c
+	}
+
+	//headers are decoded with the hash node reported , see nodeHeader
+	headers := make(chan *nodeHeader)
+	sub, err := es.wsClient.EthSubscribe(ctx, headers, "newHeads")
+	if err != nil {
+		es.wsClient = nil
+		return false, err
+	}
+	defer sub.Unsubscribe()
+
+	//catch up blocks arrived while disconnected
+	es.requestCatchUp()
+
+	received := false
+	for {
+		select {
+		case err := <-sub.Err():
+			es.wsClient = nil
+			return received, err
+		case header := <-headers: //get new block event
+			received = true
+			es.onNewHead(ctx, header)
+		case <-ctx.Done():
+			return received, nil
+		}
+	}
+}
+
+// requestCatchUp ask catch up loop to scan to latest , merged into the pending request if there is one
+func (es *ethScan) requestCatchUp() {
+	select {
+	case es.catchUp <- struct{}{}:
+	default:
+	}
+}
+
+// runCatchUp scan to latest on request , one scan at a time so resubscribes never run overlapping backfills
+func (es *ethScan) runCatchUp(ctx context.Context) {
+	for {
+		select {
+		case <-es.catchUp:
+			es.scanToLatest(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// pollNewBlock poll latest header on interval , for endpoints without websocket support
+func (es *ethScan) pollNewBlock(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var lastHash common.Hash
+	for {
+		select {
+		case <-ticker.C:
+			header, err := es.fetchHeader(ctx, "latest")
+			if err != nil {
+				log.Error().Err(err).Msg("poll latest header fail")
+				continue
+			}
+
+			if header.Hash == lastHash {
+				continue
+			}
+			lastHash = header.Hash
+			es.onNewHead(ctx, header)
+		case <-ctx.Done():
+			log.Print("break poll loop")
+			return
+		}
+	}
+}
+
+// onNewHead save new block and stabilize confirmed blocks .
+// Heads skipped by polling or reconnect also get their confirmed block stabilized.
+func (es *ethScan) onNewHead(ctx context.Context, header *nodeHeader) {
+	blockNum := header.Number.Uint64()
+	log.Info().Uint64("block_num", blockNum).Msg("get new block")
+	go es.setNewBlock(ctx, header)
+
+	from := blockNum
+	if es.lastHeadNum > 0 && es.lastHeadNum < blockNum {
+		from = es.lastHeadNum + 1
+	}
+	if blockNum-from > uint64(confirmedNum) {
+		from = blockNum - uint64(confirmedNum)
+	}
+
+	for n := from; n <= blockNum; n++ {
+		if n >= uint64(confirmedNum) {
+			go es.setOldBlock(ctx, n-uint64(confirmedNum))
+		}
+	}
+
+	if blockNum > es.lastHeadNum {
+		es.lastHeadNum = blockNum
+	}
+}
@@ -0,0 +1,101 @@
+package eth
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"github.com/ryanCool/ethService/domain"
+)
+
+// maxReorgDepth is the max number of blocks walked back looking for common ancestor
+const maxReorgDepth = 256
+
+// handleReorg verify new header links to the stored chain by parent hash .
+// On mismatch walk back to the common ancestor , drop orphaned blocks and record the reorg event.
+// Returns the first block need to be indexed again , or header number if chain is intact.
+func (es *ethScan) handleReorg(ctx context.Context, header *nodeHeader) (uint64, error) {
+	es.reorgMu.Lock()
+	defer es.reorgMu.Unlock()
+
+	blockNum := header.Number.Uint64()
+	var oldHead *domain.BlockDb
+	forkNum := blockNum
+
+	//same height replaced , new header itself is not the stored one
+	stored, err := es.blockUCase.GetByNumber(ctx, blockNum)
+	if err != nil && err != domain.ErrBlockNotExist {
+		return blockNum, err
+	}
+	if stored != nil && stored.BlockHash != header.Hash.String() {
+		oldHead = &stored.BlockDb
+	}
+
+	//walk back through canonical parents until a stored hash matches , heights missing in db are walked over
+	expected := header.ParentHash
+	for depth := 0; ; depth++ {
+		if forkNum == 0 {
+			if oldHead == nil {
+				return blockNum, nil
+			}
+			return blockNum, domain.ErrNoCommonAncestor
+		}
+
+		stored, err = es.blockUCase.GetByNumber(ctx, forkNum-1)
+		if err != nil && err != domain.ErrBlockNotExist {
+			return blockNum, err
+		}
+
+		//no mismatch found and nothing stored to compare with , gap auditor takes care of missing blocks
+		if stored == nil && oldHead == nil {
+			return blockNum, nil
+		}
+
+		if stored != nil && stored.BlockHash == expected.String() {
+			break
+		}
+
+		if depth >= maxReorgDepth {
+			return blockNum, domain.ErrReorgTooDeep
+		}
+
+		if oldHead == nil {
+			oldHead = &stored.BlockDb
+		}
+
+		parent, err := es.rpcClient.HeaderByHash(ctx, expected)
+		if err != nil {
+			return blockNum, err
+		}
+		expected = parent.ParentHash
+		forkNum--
+	}
+
+	if oldHead == nil {
+		return blockNum, nil
+	}
+
+	//loop only breaks on a stored match below forkNum , so forkNum is above 0 here
+	ancestorNum := forkNum - 1
+	event := &domain.ReorgEvent{
+		AncestorNum:  ancestorNum,
+		AncestorHash: expected.String(),
+		Depth:        oldHead.BlockNum - ancestorNum,
+		OldHeadHash:  oldHead.BlockHash,
+		NewHeadHash:  header.Hash.String(),
+	}
+	log.Warn().Uint64("ancestor_num", event.AncestorNum).Uint64("depth", event.Depth).
+		Str("old_head_hash", event.OldHeadHash).Str("new_head_hash", event.NewHeadHash).Msg("chain reorg detected")
+
+	//workers still writing orphaned heights would bring their rows back after delete , wait for them first
+	es.cursor.hold(ancestorNum)
+	defer es.cursor.release()
+
+	if err = es.reorgBlocks(ctx, event); err != nil {
+		return blockNum, err
+	}
+
+	if err = es.cursor.rewind(ancestorNum, event.AncestorHash); err != nil {
+		log.Err(err).Msg("rewind sync checkpoint fail")
+	}
+
+	return forkNum, nil
+}
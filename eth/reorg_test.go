@@ -0,0 +1,163 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ryanCool/ethService/domain"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// storedBlocks is block use case over an in memory chain , recording reorgs it is asked for
+type storedBlocks struct {
+	domain.BlockUseCase
+	hashes map[uint64]string
+	reorgs []domain.ReorgEvent
+}
+
+func (s *storedBlocks) GetByNumber(ctx context.Context, blockNum uint64) (*domain.Block, error) {
+	hash, exist := s.hashes[blockNum]
+	if !exist {
+		return nil, domain.ErrBlockNotExist
+	}
+	return &domain.Block{BlockDb: domain.BlockDb{BlockNum: blockNum, BlockHash: hash}}, nil
+}
+
+func (s *storedBlocks) Reorg(ctx context.Context, event *domain.ReorgEvent) error {
+	s.reorgs = append(s.reorgs, *event)
+	for n := range s.hashes {
+		if n > event.AncestorNum {
+			delete(s.hashes, n)
+		}
+	}
+	return nil
+}
+
+type noopLedger struct {
+	domain.BalanceUseCase
+}
+
+func (noopLedger) RevertTo(ctx context.Context, blockNum uint64) error { return nil }
+
+// headerNode serves eth_getBlockByHash of raw headers over json-rpc
+func headerNode(t *testing.T, headers []json.RawMessage) *ethclient.Client {
+	byHash := map[string]json.RawMessage{}
+	for _, raw := range headers {
+		var head nodeHeader
+		if err := json.Unmarshal(raw, &head); err != nil {
+			t.Fatal(err)
+		}
+		byHash[head.Hash.String()] = raw
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params []interface{}   `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			return
+		}
+
+		result := json.RawMessage("null")
+		if hash, ok := req.Params[0].(string); ok && req.Method == "eth_getBlockByHash" && byHash[hash] != nil {
+			result = byHash[hash]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := rpc.DialHTTP(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Close)
+	return ethclient.NewClient(c)
+}
+
+func newReorgTestScan(t *testing.T, blocks *storedBlocks) (*ethScan, *[]savedCheckpoint) {
+	raw := loadShanghaiHeaders(t)
+	cursor, saved := newTestCursor(17034872)
+	return &ethScan{
+		rpcClient:    headerNode(t, raw),
+		blockUCase:   blocks,
+		balanceUcase: noopLedger{},
+		cursor:       cursor,
+		reorgMu:      &sync.Mutex{},
+		ledgerMu:     &sync.Mutex{},
+	}, saved
+}
+
+func shanghaiHeads(t *testing.T) (*nodeHeader, *nodeHeader) {
+	raw := loadShanghaiHeaders(t)
+	var parent, head *nodeHeader
+	if err := json.Unmarshal(raw[0], &parent); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(raw[1], &head); err != nil {
+		t.Fatal(err)
+	}
+	return parent, head
+}
+
+func TestHandleReorgIntactChain(t *testing.T) {
+	parent, head := shanghaiHeads(t)
+
+	//head already stored by an earlier event , both with node reported hashes
+	blocks := &storedBlocks{hashes: map[uint64]string{
+		17034870: parent.Hash.String(),
+		17034871: head.Hash.String(),
+	}}
+	es, _ := newReorgTestScan(t, blocks)
+
+	forkNum, err := es.handleReorg(context.Background(), head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forkNum != 17034871 || len(blocks.reorgs) != 0 {
+		t.Fatalf("fork num %d reorgs %+v , want 17034871 and no reorg", forkNum, blocks.reorgs)
+	}
+}
+
+func TestHandleReorgWalkBack(t *testing.T) {
+	parent, head := shanghaiHeads(t)
+
+	//parent height holds an orphaned block , grand parent is the common ancestor
+	orphan := "0x1111111111111111111111111111111111111111111111111111111111111111"
+	blocks := &storedBlocks{hashes: map[uint64]string{
+		17034869: parent.ParentHash.String(),
+		17034870: orphan,
+	}}
+	es, saved := newReorgTestScan(t, blocks)
+
+	forkNum, err := es.handleReorg(context.Background(), head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forkNum != 17034870 {
+		t.Fatalf("fork num %d , want 17034870", forkNum)
+	}
+
+	want := []domain.ReorgEvent{{
+		AncestorNum:  17034869,
+		AncestorHash: parent.ParentHash.String(),
+		Depth:        1,
+		OldHeadHash:  orphan,
+		NewHeadHash:  head.Hash.String(),
+	}}
+	if !reflect.DeepEqual(blocks.reorgs, want) {
+		t.Fatalf("reorgs %+v\nwant %+v", blocks.reorgs, want)
+	}
+
+	if !reflect.DeepEqual(*saved, []savedCheckpoint{{17034869, parent.ParentHash.String()}}) {
+		t.Fatalf("checkpoint saved %+v , want rewind to ancestor", *saved)
+	}
+}
@@ -0,0 +1,210 @@
+package eth
+
+import (
+	"sort"
+	"sync"
+)
+
+// syncCursor tracks the contiguous range of fully indexed blocks .
+// Blocks can finish out of order in the worker pool , the cursor only moves forward
+// when every block below it is done , so the persisted checkpoint never skips a hole.
+type syncCursor struct {
+	mu sync.Mutex
+
+	// next is the first block not yet fully indexed
+	next uint64
+
+	// scheduled is the first block not yet handed to a worker
+	scheduled uint64
+
+	// done keeps blocks above next which already finished , with their hash
+	done map[uint64]string
+
+	// running keeps blocks being written by a worker
+	running map[uint64]bool
+
+	// failed keeps blocks which could not be saved after retries , handed to gap auditor so next stops waiting on them soon
+	failed map[uint64]bool
+
+	// holding is set while a reorg replaces blocks above heldAbove , workers wait to begin those heights
+	holding   bool
+	heldAbove uint64
+
+	// changed is broadcast when a worker ends or hold is released
+	changed *sync.Cond
+
+	// save persists the last fully indexed block
+	save func(blockNum uint64, blockHash string) error
+}
+
+func newSyncCursor(next uint64, save func(blockNum uint64, blockHash string) error) *syncCursor {
+	c := &syncCursor{
+		next:      next,
+		scheduled: next,
+		done:      map[uint64]string{},
+		running:   map[uint64]bool{},
+		failed:    map[uint64]bool{},
+		save:      save,
+	}
+	c.changed = sync.NewCond(&c.mu)
+	return c
+}
+
+// schedule marks blocks up to blockNum as handed to worker and returns the first block
+// not scheduled before . Returned value greater than blockNum means nothing new to do.
+func (c *syncCursor) schedule(blockNum uint64) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	from := c.scheduled
+	if blockNum >= c.scheduled {
+		c.scheduled = blockNum + 1
+	}
+	return from
+}
+
+// begin marks blockNum as being written , false if another worker is already on it .
+// Heights held by a reorg wait until it is released.
+func (c *syncCursor) begin(blockNum uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.holding && blockNum > c.heldAbove {
+		c.changed.Wait()
+	}
+
+	if c.running[blockNum] {
+		return false
+	}
+	c.running[blockNum] = true
+	return true
+}
+
+// end marks blockNum as no longer being written
+func (c *syncCursor) end(blockNum uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.running, blockNum)
+	c.changed.Broadcast()
+}
+
+// hold stops new writes above blockNum and waits until running ones end , so a reorg deleting those heights
+// is not undone by a worker still writing an orphaned block . Reorgs are serialized , only one hold at a time.
+func (c *syncCursor) hold(blockNum uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.holding, c.heldAbove = true, blockNum
+	for {
+		busy := false
+		for n := range c.running {
+			if n > blockNum {
+				busy = true
+				break
+			}
+		}
+		if !busy {
+			return
+		}
+		c.changed.Wait()
+	}
+}
+
+// release lets workers held by hold begin again
+func (c *syncCursor) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.holding = false
+	c.changed.Broadcast()
+}
+
+// latest returns the highest block handed to worker
+func (c *syncCursor) latest() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.scheduled == 0 {
+		return 0
+	}
+	return c.scheduled - 1
+}
+
+// rewind moves cursor back to blockNum after a reorg , every block above it will be indexed again
+func (c *syncCursor) rewind(blockNum uint64, blockHash string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for n := range c.done {
+		if n > blockNum {
+			delete(c.done, n)
+		}
+	}
+	for n := range c.failed {
+		if n > blockNum {
+			delete(c.failed, n)
+		}
+	}
+
+	if blockNum+1 >= c.next {
+		return nil
+	}
+	c.next = blockNum + 1
+	return c.save(blockNum, blockHash)
+}
+
+// complete records blockNum as fully indexed and persists the cursor if it moved forward
+func (c *syncCursor) complete(blockNum uint64, blockHash string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.failed, blockNum)
+	if blockNum < c.next {
+		return nil
+	}
+	c.done[blockNum] = blockHash
+
+	var lastHash string
+	advanced := false
+	for {
+		hash, ok := c.done[c.next]
+		if !ok {
+			break
+		}
+		delete(c.done, c.next)
+		lastHash = hash
+		c.next++
+		advanced = true
+	}
+
+	if !advanced {
+		return nil
+	}
+	return c.save(c.next-1, lastHash)
+}
+
+// fail records blockNum as given up by its worker , it is retried by gap auditor through takeFailed
+func (c *syncCursor) fail(blockNum uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if blockNum >= c.next {
+		c.failed[blockNum] = true
+	}
+}
+
+// takeFailed returns failed blocks in ascending order and forgets them
+func (c *syncCursor) takeFailed() []uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	res := make([]uint64, 0, len(c.failed))
+	for blockNum := range c.failed {
+		res = append(res, blockNum)
+	}
+	c.failed = map[uint64]bool{}
+
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
@@ -0,0 +1,203 @@
+package eth
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type savedCheckpoint struct {
+	blockNum  uint64
+	blockHash string
+}
+
+func newTestCursor(next uint64) (*syncCursor, *[]savedCheckpoint) {
+	var saved []savedCheckpoint
+	c := newSyncCursor(next, func(blockNum uint64, blockHash string) error {
+		saved = append(saved, savedCheckpoint{blockNum, blockHash})
+		return nil
+	})
+	return c, &saved
+}
+
+func TestSyncCursorSchedule(t *testing.T) {
+	c, _ := newTestCursor(10)
+
+	if from := c.schedule(15); from != 10 {
+		t.Fatalf("first schedule from = %d, want 10", from)
+	}
+	if latest := c.latest(); latest != 15 {
+		t.Fatalf("latest = %d, want 15", latest)
+	}
+
+	//already scheduled head returns a from above it
+	if from := c.schedule(12); from != 16 {
+		t.Fatalf("schedule of old head from = %d, want 16", from)
+	}
+	if from := c.schedule(20); from != 16 {
+		t.Fatalf("schedule of new head from = %d, want 16", from)
+	}
+}
+
+func TestSyncCursorBegin(t *testing.T) {
+	c, _ := newTestCursor(0)
+
+	if !c.begin(5) {
+		t.Fatal("first begin refused")
+	}
+	if c.begin(5) {
+		t.Fatal("second begin of running block accepted")
+	}
+
+	c.end(5)
+	if !c.begin(5) {
+		t.Fatal("begin after end refused")
+	}
+}
+
+func TestSyncCursorCompleteOutOfOrder(t *testing.T) {
+	c, saved := newTestCursor(10)
+
+	steps := []struct {
+		blockNum uint64
+		want     []savedCheckpoint
+	}{
+		{12, nil},
+		{11, nil},
+		{10, []savedCheckpoint{{12, "0x12"}}},
+		{9, []savedCheckpoint{{12, "0x12"}}},
+		{14, []savedCheckpoint{{12, "0x12"}}},
+		{13, []savedCheckpoint{{12, "0x12"}, {14, "0x14"}}},
+	}
+
+	for _, step := range steps {
+		if err := c.complete(step.blockNum, hashOf(step.blockNum)); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(*saved, step.want) {
+			t.Fatalf("after complete %d saved = %v, want %v", step.blockNum, *saved, step.want)
+		}
+	}
+
+	if len(c.done) != 0 {
+		t.Fatalf("done keeps %d blocks below next", len(c.done))
+	}
+}
+
+func TestSyncCursorRewind(t *testing.T) {
+	c, saved := newTestCursor(10)
+	for _, n := range []uint64{10, 11, 12, 14} {
+		if err := c.complete(n, hashOf(n)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := c.rewind(11, "0x11"); err != nil {
+		t.Fatal(err)
+	}
+	want := []savedCheckpoint{{10, "0x10"}, {11, "0x11"}, {12, "0x12"}, {11, "0x11"}}
+	if !reflect.DeepEqual(*saved, want) {
+		t.Fatalf("saved = %v, want %v", *saved, want)
+	}
+	if c.next != 12 {
+		t.Fatalf("next = %d, want 12", c.next)
+	}
+	if _, ok := c.done[14]; ok {
+		t.Fatal("rewind keeps orphaned block 14 done")
+	}
+
+	//rewind above cursor only forgets orphaned blocks
+	if err := c.complete(15, "0x15"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.rewind(13, "0x13"); err != nil {
+		t.Fatal(err)
+	}
+	if len(*saved) != 4 || c.next != 12 || len(c.done) != 0 {
+		t.Fatalf("rewind above cursor moved it , saved = %v next = %d done = %v", *saved, c.next, c.done)
+	}
+}
+
+func TestSyncCursorFailed(t *testing.T) {
+	c, _ := newTestCursor(10)
+
+	c.fail(13)
+	c.fail(11)
+	c.fail(9)
+	if failed := c.takeFailed(); !reflect.DeepEqual(failed, []uint64{11, 13}) {
+		t.Fatalf("failed = %v, want [11 13]", failed)
+	}
+	if failed := c.takeFailed(); len(failed) != 0 {
+		t.Fatalf("failed taken twice : %v", failed)
+	}
+
+	c.fail(12)
+	c.fail(15)
+	if err := c.complete(12, "0x12"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.rewind(11, "0x11"); err != nil {
+		t.Fatal(err)
+	}
+	if failed := c.takeFailed(); len(failed) != 0 {
+		t.Fatalf("completed or orphaned blocks still failed : %v", failed)
+	}
+}
+
+func TestSyncCursorHold(t *testing.T) {
+	c, _ := newTestCursor(0)
+
+	if !c.begin(12) {
+		t.Fatal("begin refused")
+	}
+
+	held := make(chan struct{})
+	go func() {
+		c.hold(10)
+		close(held)
+	}()
+
+	select {
+	case <-held:
+		t.Fatal("hold returned while block above ancestor still running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	c.end(12)
+	select {
+	case <-held:
+	case <-time.After(time.Second):
+		t.Fatal("hold still waiting after running block ended")
+	}
+
+	if !c.begin(10) {
+		t.Fatal("begin at held height refused")
+	}
+	c.end(10)
+
+	begun := make(chan bool)
+	go func() {
+		begun <- c.begin(11)
+	}()
+
+	select {
+	case <-begun:
+		t.Fatal("begin above held height not waiting")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	c.release()
+	select {
+	case ok := <-begun:
+		if !ok {
+			t.Fatal("begin after release refused")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("begin still waiting after release")
+	}
+}
+
+func hashOf(blockNum uint64) string {
+	return fmt.Sprintf("0x%d", blockNum)
+}
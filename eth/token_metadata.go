@@ -0,0 +1,164 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog/log"
+	"github.com/ryanCool/ethService/domain"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// tokenResolveBatchSize is the number of token contracts resolved per round
+const tokenResolveBatchSize = 100
+
+var (
+	nameSelector        = crypto.Keccak256([]byte("name()"))[:4]
+	symbolSelector      = crypto.Keccak256([]byte("symbol()"))[:4]
+	decimalsSelector    = crypto.Keccak256([]byte("decimals()"))[:4]
+	totalSupplySelector = crypto.Keccak256([]byte("totalSupply()"))[:4]
+)
+
+// callFailedErrors are error messages nodes return when execution of contract call fails ,
+// contracts without the method revert or hit invalid opcode of old compilers
+var callFailedErrors = []string{"execution reverted", "invalid opcode", "invalid jump destination"}
+
+// revertErrorCode is json rpc error code of call reverted with data
+const revertErrorCode = 3
+
+var stringArguments = abi.Arguments{{Type: mustNewType("string")}}
+
+func mustNewType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// refreshTokens periodically resolve metadata of new token contracts , and re-resolve tokens older than tokenRefreshInterval
+func (es *ethScan) refreshTokens(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := es.resolveTokens(ctx); err != nil {
+				log.Err(err).Msg("resolve token metadata fail")
+			}
+		case <-ctx.Done():
+			log.Print("break token metadata loop")
+			return
+		}
+	}
+}
+
+func (es *ethScan) resolveTokens(ctx context.Context) error {
+	addresses, err := es.tokenUcase.ListTokensToResolve(ctx, time.Now().Add(-tokenRefreshInterval), tokenResolveBatchSize)
+	if err != nil || len(addresses) == 0 {
+		return err
+	}
+
+	tokens := make([]domain.Token, 0, len(addresses))
+	var failed []string
+	for _, address := range addresses {
+		token, err := es.resolveToken(ctx, common.HexToAddress(address))
+		if err != nil {
+			//keep previous metadata , token is picked again after tokens with fewer failures
+			log.Warn().Err(err).Str("token", address).Msg("resolve token fail")
+			failed = append(failed, address)
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err = es.tokenUcase.MarkTokensFailed(ctx, failed); err != nil {
+		return err
+	}
+
+	return es.tokenUcase.SaveTokens(ctx, tokens)
+}
+
+// resolveToken read token metadata through contract calls . A call that fails in execution or returns
+// malformed data leaves its field empty , e.g. ERC-721 has no decimals . Any other error is returned.
+func (es *ethScan) resolveToken(ctx context.Context, address common.Address) (domain.Token, error) {
+	token := domain.Token{Address: address.String(), Resolved: true}
+
+	out, err := es.callToken(ctx, address, nameSelector)
+	if err != nil {
+		return token, err
+	}
+	token.Name = decodeTokenString(out)
+
+	if out, err = es.callToken(ctx, address, symbolSelector); err != nil {
+		return token, err
+	}
+	token.Symbol = decodeTokenString(out)
+
+	if out, err = es.callToken(ctx, address, decimalsSelector); err != nil {
+		return token, err
+	}
+	if len(out) >= 32 {
+		if decimals := new(big.Int).SetBytes(out[:32]); decimals.IsUint64() && decimals.Uint64() <= 255 {
+			d := uint8(decimals.Uint64())
+			token.Decimals = &d
+		}
+	}
+
+	if out, err = es.callToken(ctx, address, totalSupplySelector); err != nil {
+		return token, err
+	}
+	if len(out) >= 32 {
+		totalSupply := new(big.Int).SetBytes(out[:32]).String()
+		token.TotalSupply = &totalSupply
+	}
+
+	return token, nil
+}
+
+// callToken call method of token contract at latest block , a call failing in execution returns no output and no error
+func (es *ethScan) callToken(ctx context.Context, address common.Address, selector []byte) ([]byte, error) {
+	out, err := es.rpcClient.CallContract(ctx, ethereum.CallMsg{To: &address, Data: selector}, nil)
+	if err != nil && isCallFailed(err) {
+		log.Debug().Err(err).Str("token", address.String()).Msg("token call failed in execution")
+		return nil, nil
+	}
+	return out, err
+}
+
+// isCallFailed reports whether err is from execution of the call , not from transport or node
+func isCallFailed(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == revertErrorCode {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, s := range callFailedErrors {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeTokenString decode name / symbol return , abi string or bytes32 used by early tokens like MKR .
+// NUL and invalid utf-8 are dropped since postgres text can not hold them.
+func decodeTokenString(out []byte) string {
+	var s string
+	if values, err := stringArguments.Unpack(out); err == nil {
+		s = values[0].(string)
+	} else if len(out) == 32 {
+		s = string(out)
+	}
+
+	s = strings.ReplaceAll(strings.ToValidUTF8(s, ""), "\x00", "")
+	return strings.TrimSpace(s)
+}
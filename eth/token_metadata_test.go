@@ -0,0 +1,127 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ryanCool/ethService/domain"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rpcError is json rpc error as returned by rpc client
+type rpcError struct {
+	code int
+	msg  string
+}
+
+func (e rpcError) Error() string  { return e.msg }
+func (e rpcError) ErrorCode() int { return e.code }
+
+func TestIsCallFailed(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{rpcError{3, "execution reverted: ERC721: no decimals"}, true},
+		{rpcError{3, "reverted"}, true},
+		{rpcError{-32000, "execution reverted"}, true},
+		{errors.New("invalid opcode: INVALID"), true},
+		{fmt.Errorf("call: %w", rpcError{-32000, "invalid jump destination"}), true},
+		{rpcError{429, "Too Many Requests"}, false},
+		{rpcError{-32000, "header not found"}, false},
+		{context.DeadlineExceeded, false},
+		{errors.New("dial tcp 127.0.0.1:8545: connect: connection refused"), false},
+	}
+
+	for _, c := range cases {
+		if got := isCallFailed(c.err); got != c.want {
+			t.Errorf("isCallFailed(%v) = %v , want %v", c.err, got, c.want)
+		}
+	}
+}
+
+// queuedTokens is token use case handing out a fixed queue , recording saved and failed tokens
+type queuedTokens struct {
+	domain.TokenUseCase
+	queue  []string
+	saved  []domain.Token
+	failed []string
+}
+
+func (q *queuedTokens) ListTokensToResolve(ctx context.Context, staleBefore time.Time, limit int) ([]string, error) {
+	return q.queue, nil
+}
+
+func (q *queuedTokens) SaveTokens(ctx context.Context, tokens []domain.Token) error {
+	q.saved = append(q.saved, tokens...)
+	return nil
+}
+
+func (q *queuedTokens) MarkTokensFailed(ctx context.Context, addresses []string) error {
+	q.failed = append(q.failed, addresses...)
+	return nil
+}
+
+// callNode answers every eth_call with an execution revert , or a node side error for contracts in down
+func callNode(t *testing.T, down map[string]bool) *ethclient.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Params []json.RawMessage `json:"params"`
+		}
+		var msg struct {
+			To string `json:"to"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			return
+		}
+		if err := json.Unmarshal(req.Params[0], &msg); err != nil {
+			t.Error(err)
+			return
+		}
+
+		rpcErr := map[string]interface{}{"code": 3, "message": "execution reverted"}
+		if down[strings.ToLower(msg.To)] {
+			rpcErr = map[string]interface{}{"code": -32000, "message": "header not found"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "error": rpcErr})
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := rpc.DialHTTP(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Close)
+	return ethclient.NewClient(c)
+}
+
+func TestResolveTokensMarksFailed(t *testing.T) {
+	const (
+		reverting = "0x00000000000000000000000000000000000000aa"
+		down      = "0x00000000000000000000000000000000000000bb"
+	)
+	tokens := &queuedTokens{queue: []string{reverting, down}}
+	es := &ethScan{rpcClient: callNode(t, map[string]bool{down: true}), tokenUcase: tokens}
+
+	if err := es.resolveTokens(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tokens.saved) != 1 || !strings.EqualFold(tokens.saved[0].Address, reverting) || !tokens.saved[0].Resolved {
+		t.Fatalf("saved %+v , want reverting token resolved with empty fields", tokens.saved)
+	}
+	if !reflect.DeepEqual(tokens.failed, []string{down}) {
+		t.Fatalf("failed %v , want %v", tokens.failed, []string{down})
+	}
+}
@@ -0,0 +1,125 @@
+package eth
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog/log"
+	"github.com/ryanCool/ethService/domain"
+	"math/big"
+)
+
+var (
+	// transferEventTopic is topic0 of Transfer(address,address,uint256) , shared by ERC-20 and ERC-721
+	transferEventTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+	transferSingleEventTopic = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+	transferBatchEventTopic  = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+)
+
+// transferBatchArgs are the non indexed ids and values of TransferBatch
+var transferBatchArgs = func() abi.Arguments {
+	uint256Array, err := abi.NewType("uint256[]", "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return abi.Arguments{{Name: "ids", Type: uint256Array}, {Name: "values", Type: uint256Array}}
+}()
+
+// decodeTokenTransfers decode ERC-20 Transfer logs . ERC-20 indexes from and to only ,
+// so the log has three topics and value in data , ERC-721 indexes token id as the fourth topic.
+func decodeTokenTransfers(logs []*types.Log) []domain.TokenTransfer {
+	var transfers []domain.TokenTransfer
+	for _, l := range logs {
+		if len(l.Topics) != 3 || l.Topics[0] != transferEventTopic || len(l.Data) != common.HashLength {
+			continue
+		}
+
+		transfers = append(transfers, domain.TokenTransfer{
+			TxHash:      l.TxHash.String(),
+			BlockHash:   l.BlockHash.String(),
+			BlockNum:    l.BlockNumber,
+			TxIndex:     l.TxIndex,
+			LogIndex:    int(l.Index),
+			Token:       l.Address.String(),
+			FromAddress: common.BytesToAddress(l.Topics[1].Bytes()).String(),
+			ToAddress:   common.BytesToAddress(l.Topics[2].Bytes()).String(),
+			Value:       new(big.Int).SetBytes(l.Data).String(),
+		})
+	}
+
+	return transfers
+}
+
+// decodeNftTransfers decode ERC-721 Transfer and ERC-1155 TransferSingle / TransferBatch logs ,
+// batch transfer is expanded into one transfer per token id.
+func decodeNftTransfers(logs []*types.Log) []domain.NftTransfer {
+	var transfers []domain.NftTransfer
+	for _, l := range logs {
+		if len(l.Topics) != 4 {
+			continue
+		}
+
+		transfer := domain.NftTransfer{
+			TxHash:    l.TxHash.String(),
+			BlockHash: l.BlockHash.String(),
+			BlockNum:  l.BlockNumber,
+			TxIndex:   l.TxIndex,
+			LogIndex:  int(l.Index),
+			Token:     l.Address.String(),
+		}
+
+		switch l.Topics[0] {
+		case transferEventTopic:
+			//ERC-721 indexes every argument , a 4-topic Transfer carrying data is not one
+			if len(l.Data) != 0 {
+				continue
+			}
+			transfer.Standard = domain.NftStandardERC721
+			transfer.FromAddress = common.BytesToAddress(l.Topics[1].Bytes()).String()
+			transfer.ToAddress = common.BytesToAddress(l.Topics[2].Bytes()).String()
+			transfer.TokenID = l.Topics[3].Big().String()
+			transfer.Amount = "1"
+			transfers = append(transfers, transfer)
+		case transferSingleEventTopic:
+			if len(l.Data) != 2*common.HashLength {
+				continue
+			}
+			setERC1155Parties(&transfer, l)
+			transfer.TokenID = new(big.Int).SetBytes(l.Data[:common.HashLength]).String()
+			transfer.Amount = new(big.Int).SetBytes(l.Data[common.HashLength:]).String()
+			transfers = append(transfers, transfer)
+		case transferBatchEventTopic:
+			values, err := transferBatchArgs.Unpack(l.Data)
+			if err != nil {
+				log.Warn().Err(err).Str("tx_hash", l.TxHash.String()).Uint("log_index", l.Index).Msg("decode transfer batch fail")
+				continue
+			}
+
+			ids, amounts := values[0].([]*big.Int), values[1].([]*big.Int)
+			if len(ids) != len(amounts) {
+				continue
+			}
+
+			setERC1155Parties(&transfer, l)
+			for i := range ids {
+				t := transfer
+				t.BatchIndex = i
+				t.TokenID = ids[i].String()
+				t.Amount = amounts[i].String()
+				transfers = append(transfers, t)
+			}
+		}
+	}
+
+	return transfers
+}
+
+// setERC1155Parties fill operator , from and to indexed by ERC-1155 transfer events
+func setERC1155Parties(transfer *domain.NftTransfer, l *types.Log) {
+	transfer.Standard = domain.NftStandardERC1155
+	transfer.Operator = common.BytesToAddress(l.Topics[1].Bytes()).String()
+	transfer.FromAddress = common.BytesToAddress(l.Topics[2].Bytes()).String()
+	transfer.ToAddress = common.BytesToAddress(l.Topics[3].Bytes()).String()
+}
@@ -0,0 +1,176 @@
+package eth
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ryanCool/ethService/domain"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+var (
+	testToken = common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	testTx    = common.HexToHash("0xabcd")
+	testBlock = common.HexToHash("0xbeef")
+)
+
+// transferLog build log of testToken at position index of block 100
+func transferLog(index uint, data []byte, topics ...common.Hash) *types.Log {
+	return &types.Log{
+		Address:     testToken,
+		Topics:      topics,
+		Data:        data,
+		BlockNumber: 100,
+		TxHash:      testTx,
+		TxIndex:     3,
+		BlockHash:   testBlock,
+		Index:       index,
+	}
+}
+
+func addressTopic(a common.Address) common.Hash {
+	return common.BytesToHash(a.Bytes())
+}
+
+func uint256(v int64) []byte {
+	return common.BigToHash(big.NewInt(v)).Bytes()
+}
+
+func TestDecodeTokenTransfers(t *testing.T) {
+	cases := []struct {
+		name string
+		log  *types.Log
+		want []domain.TokenTransfer
+	}{
+		{
+			name: "erc20 transfer",
+			log:  transferLog(7, uint256(1000000), transferEventTopic, addressTopic(testFrom), addressTopic(testTo)),
+			want: []domain.TokenTransfer{{
+				TxHash:      testTx.String(),
+				BlockHash:   testBlock.String(),
+				BlockNum:    100,
+				TxIndex:     3,
+				LogIndex:    7,
+				Token:       testToken.String(),
+				FromAddress: testFrom.String(),
+				ToAddress:   testTo.String(),
+				Value:       "1000000",
+			}},
+		},
+		{
+			name: "erc721 transfer with token id topic",
+			log:  transferLog(7, nil, transferEventTopic, addressTopic(testFrom), addressTopic(testTo), common.BigToHash(big.NewInt(42))),
+		},
+		{
+			name: "value shorter than 32 bytes",
+			log:  transferLog(7, []byte{0x01}, transferEventTopic, addressTopic(testFrom), addressTopic(testTo)),
+		},
+		{
+			name: "value longer than 32 bytes",
+			log:  transferLog(7, append(uint256(1), 0x00), transferEventTopic, addressTopic(testFrom), addressTopic(testTo)),
+		},
+		{
+			name: "other event",
+			log:  transferLog(7, uint256(1), common.HexToHash("0x01"), addressTopic(testFrom), addressTopic(testTo)),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := decodeTokenTransfers([]*types.Log{c.log})
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("decoded %+v , want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestDecodeNftTransfers(t *testing.T) {
+	operator := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	batchData := func(ids []*big.Int, amounts []*big.Int) []byte {
+		data, err := transferBatchArgs.Pack(ids, amounts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	nft := func(standard string, batchIndex int, tokenID string, amount string) domain.NftTransfer {
+		transfer := domain.NftTransfer{
+			TxHash:      testTx.String(),
+			BlockHash:   testBlock.String(),
+			BlockNum:    100,
+			TxIndex:     3,
+			LogIndex:    9,
+			BatchIndex:  batchIndex,
+			Token:       testToken.String(),
+			Standard:    standard,
+			FromAddress: testFrom.String(),
+			ToAddress:   testTo.String(),
+			TokenID:     tokenID,
+			Amount:      amount,
+		}
+		if standard == domain.NftStandardERC1155 {
+			transfer.Operator = operator.String()
+		}
+		return transfer
+	}
+
+	cases := []struct {
+		name string
+		log  *types.Log
+		want []domain.NftTransfer
+	}{
+		{
+			name: "erc721 transfer",
+			log:  transferLog(9, nil, transferEventTopic, addressTopic(testFrom), addressTopic(testTo), common.BigToHash(big.NewInt(8520))),
+			want: []domain.NftTransfer{nft(domain.NftStandardERC721, 0, "8520", "1")},
+		},
+		{
+			name: "4-topic transfer with data is not erc721",
+			log:  transferLog(9, uint256(1), transferEventTopic, addressTopic(testFrom), addressTopic(testTo), common.BigToHash(big.NewInt(8520))),
+		},
+		{
+			name: "erc20 transfer",
+			log:  transferLog(9, uint256(1), transferEventTopic, addressTopic(testFrom), addressTopic(testTo)),
+		},
+		{
+			name: "transfer single",
+			log: transferLog(9, append(uint256(7), uint256(250)...),
+				transferSingleEventTopic, addressTopic(operator), addressTopic(testFrom), addressTopic(testTo)),
+			want: []domain.NftTransfer{nft(domain.NftStandardERC1155, 0, "7", "250")},
+		},
+		{
+			name: "transfer single with short data",
+			log:  transferLog(9, uint256(7), transferSingleEventTopic, addressTopic(operator), addressTopic(testFrom), addressTopic(testTo)),
+		},
+		{
+			name: "transfer batch expanded by token id",
+			log: transferLog(9, batchData([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}, []*big.Int{big.NewInt(10), big.NewInt(20), big.NewInt(30)}),
+				transferBatchEventTopic, addressTopic(operator), addressTopic(testFrom), addressTopic(testTo)),
+			want: []domain.NftTransfer{
+				nft(domain.NftStandardERC1155, 0, "1", "10"),
+				nft(domain.NftStandardERC1155, 1, "2", "20"),
+				nft(domain.NftStandardERC1155, 2, "3", "30"),
+			},
+		},
+		{
+			name: "transfer batch with mismatched ids and amounts",
+			log: transferLog(9, batchData([]*big.Int{big.NewInt(1), big.NewInt(2)}, []*big.Int{big.NewInt(10)}),
+				transferBatchEventTopic, addressTopic(operator), addressTopic(testFrom), addressTopic(testTo)),
+		},
+		{
+			name: "transfer batch with malformed data",
+			log:  transferLog(9, uint256(1), transferBatchEventTopic, addressTopic(operator), addressTopic(testFrom), addressTopic(testTo)),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := decodeNftTransfers([]*types.Log{c.log})
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("decoded %+v , want %+v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package eth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+	"github.com/ryanCool/ethService/domain"
+	"time"
+)
+
+// refreshWatchlist periodically reload watched addresses , so api changes reach scanner without restart
+func (es *ethScan) refreshWatchlist(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := es.loadWatchlist(ctx); err != nil {
+				log.Err(err).Msg("reload watchlist fail")
+			}
+		case <-ctx.Done():
+			log.Print("break watchlist loop")
+			return
+		}
+	}
+}
+
+func (es *ethScan) loadWatchlist(ctx context.Context) error {
+	list, err := es.watchlistUcase.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	watched := make(map[common.Address]bool, len(list))
+	for _, w := range list {
+		watched[common.HexToAddress(w.Address)] = true
+	}
+
+	es.watchMu.Lock()
+	es.watched = watched
+	es.watchMu.Unlock()
+	return nil
+}
+
+// saveSnapshots record native balance at block of watched addresses appearing in block ,
+// as transaction sender or receiver , miner or withdrawal receiver
+func (es *ethScan) saveSnapshots(ctx context.Context, block *domain.BlockDb, rawBlock *types.Block) error {
+	es.watchMu.RLock()
+	watched := es.watched
+	es.watchMu.RUnlock()
+
+	if len(watched) == 0 {
+		return nil
+	}
+
+	appeared := map[common.Address]bool{}
+	mark := func(address common.Address) {
+		if watched[address] {
+			appeared[address] = true
+		}
+	}
+
+	mark(rawBlock.Coinbase())
+	for _, w := range block.Withdrawals {
+		mark(common.HexToAddress(w.Address))
+	}
+	for _, transaction := range rawBlock.Transactions() {
+		if from, err := types.Sender(types.LatestSignerForChainID(transaction.ChainId()), transaction); err == nil {
+			mark(from)
+		}
+		if transaction.To() != nil {
+			mark(*transaction.To())
+		}
+	}
+
+	var snapshots []domain.BalanceSnapshot
+	for address := range appeared {
+		balance, err := es.rpcClient.BalanceAt(ctx, address, rawBlock.Number())
+		if err != nil {
+			//node without state of this block , e.g. backfill on a non archive node
+			log.Warn().Err(err).Str("address", address.String()).Uint64("block_num", block.BlockNum).Msg("get balance fail , skip snapshot")
+			continue
+		}
+
+		snapshots = append(snapshots, domain.BalanceSnapshot{
+			Address:   address.String(),
+			BlockHash: block.BlockHash,
+			BlockNum:  block.BlockNum,
+			BlockTime: block.BlockTime,
+			Balance:   balance.String(),
+		})
+	}
+
+	return es.watchlistUcase.SaveSnapshots(ctx, snapshots)
+}
@@ -1,36 +1,169 @@
 package ethclient
 
 import (
+	"context"
+	"expvar"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog/log"
 	"github.com/ryanCool/ethService/config"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
-var endpointURL, wsEndpointURL string
+var endpointURLs, wsEndpointURLs []string
+var endpointWeights []int
+var poolConfig PoolConfig
 var (
-	WsClient  *ethclient.Client
 	RpcClient *ethclient.Client
+
+	// WsClient is the websocket connection new heads are subscribed on
+	WsClient *rpc.Client
+
+	// RpcRawClient is the json-rpc connection under RpcClient , for methods ethclient does not wrap
+	RpcRawClient *rpc.Client
+
+	// RpcPool routes requests of RpcRawClient over JSON_RPC_ENDPOINT providers
+	RpcPool *Pool
 )
 
-func init() {
-	// todo use multiple endpoint to avoid 429 too many request
-	endpointURL = config.GetString("JSON_RPC_ENDPOINT")
-	wsEndpointURL = config.GetString("WS_ENDPOINT")
+// wsMu guards WsClient and wsNext while it is redialed
+var wsMu sync.Mutex
+
+// wsNext is the index of ws endpoint dialed next , moved forward when dial fails
+var wsNext int
+
+// stopHealthCheck stops pool health check on Finalize
+var stopHealthCheck context.CancelFunc
+
+// loadConfig read endpoints and pool settings , on Initialize rather than init so package loads without them
+func loadConfig() {
+	endpointURLs = splitList(config.GetString("JSON_RPC_ENDPOINT"))
+	//websocket is only dialed in ws head subscribe mode , poll mode runs without it
+	if ws, ok := config.LookupString("WS_ENDPOINT"); ok {
+		wsEndpointURLs = splitList(ws)
+	}
+
+	for _, w := range splitList(config.GetString("JSON_RPC_ENDPOINT_WEIGHTS")) {
+		weight, err := strconv.Atoi(w)
+		if err != nil {
+			panic(err)
+		}
+		endpointWeights = append(endpointWeights, weight)
+	}
+
+	poolConfig = PoolConfig{
+		Strategy:            config.GetString("RPC_POOL_STRATEGY"),
+		HealthCheckInterval: time.Duration(config.GetInt("RPC_POOL_HEALTH_CHECK_SECS")) * time.Second,
+		MaxLagBlocks:        config.GetUint64("RPC_POOL_MAX_LAG_BLOCKS"),
+		EjectErrors:         config.GetInt("RPC_POOL_EJECT_ERRORS"),
+		EjectDuration:       time.Duration(config.GetInt("RPC_POOL_EJECT_SECS")) * time.Second,
+		RequestsPerSec:      config.GetFloat64("RPC_REQUESTS_PER_SEC"),
+		CreditsPerSec:       config.GetFloat64("RPC_CREDITS_PER_SEC"),
+		MethodCredits:       map[string]float64{},
+		DefaultCredits:      1,
+		MinConcurrency:      config.GetInt("RPC_MIN_CONCURRENCY"),
+		MaxConcurrency:      config.GetInt("RPC_MAX_CONCURRENCY"),
+	}
+
+	//ticker of health check panics on non-positive interval
+	if poolConfig.HealthCheckInterval <= 0 {
+		panic("config invalid RPC_POOL_HEALTH_CHECK_SECS : must be at least 1 second")
+	}
+
+	//method credits are listed as method=credits , * sets the cost of unlisted methods
+	for _, item := range splitList(config.GetString("RPC_METHOD_CREDITS")) {
+		method, value, ok := strings.Cut(item, "=")
+		if !ok {
+			panic("config invalid RPC_METHOD_CREDITS")
+		}
+
+		credits, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			panic(err)
+		}
+
+		if method = strings.TrimSpace(method); method == "*" {
+			poolConfig.DefaultCredits = credits
+		} else {
+			poolConfig.MethodCredits[method] = credits
+		}
+	}
 }
 
 func Initialize() {
+	loadConfig()
+
 	var err error
-	RpcClient, err = ethclient.Dial(endpointURL)
+	RpcPool, err = NewPool(endpointURLs, endpointWeights, poolConfig)
 	if err != nil {
 		panic(err)
 	}
 
-	WsClient, err = ethclient.Dial(wsEndpointURL)
+	RpcRawClient, err = rpc.DialHTTPWithClient(RpcPool.URL(), &http.Client{Transport: RpcPool})
 	if err != nil {
 		panic(err)
 	}
+	RpcClient = ethclient.NewClient(RpcRawClient)
+
+	//per endpoint stats are served at /debug/vars
+	expvar.Publish("rpc_pool", expvar.Func(func() interface{} { return RpcPool.Stats() }))
+
+	var ctx context.Context
+	ctx, stopHealthCheck = context.WithCancel(context.Background())
+	go RpcPool.HealthCheck(ctx)
+}
+
+// DialWs dial websocket endpoint and replace WsClient , previous connection is closed .
+// Endpoints are tried in turn starting from the last one that worked.
+func DialWs(ctx context.Context) (*rpc.Client, error) {
+	wsMu.Lock()
+	defer wsMu.Unlock()
+
+	var lastErr error
+	for i := 0; i < len(wsEndpointURLs); i++ {
+		c, err := rpc.DialContext(ctx, wsEndpointURLs[wsNext])
+		if err != nil {
+			log.Warn().Err(err).Int("endpoint", wsNext).Msg("dial ws endpoint fail")
+			lastErr = err
+			wsNext = (wsNext + 1) % len(wsEndpointURLs)
+			continue
+		}
+
+		if WsClient != nil {
+			WsClient.Close()
+		}
+		WsClient = c
+		return c, nil
+	}
+
+	if lastErr == nil {
+		lastErr = errNoEndpoint
+	}
+	return nil, lastErr
 }
 
 func Finalize() {
+	stopHealthCheck()
 	RpcClient.Close()
-	WsClient.Close()
+
+	wsMu.Lock()
+	defer wsMu.Unlock()
+	if WsClient != nil {
+		WsClient.Close()
+	}
+}
+
+// splitList split comma separated config value , blanks are dropped
+func splitList(value string) []string {
+	var res []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
 }
@@ -0,0 +1,174 @@
+package ethclient
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// tokenBucket refills rate tokens per second up to one second of burst , nil bucket never waits
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+
+	burst := rate
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// wait take n tokens , blocking until they are refilled . Tokens are reserved up front ,
+// so a cost larger than burst still passes once its deficit is paid.
+func (b *tokenBucket) wait(ctx context.Context, n float64) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	b.tokens -= n
+	deficit := -b.tokens
+	b.mu.Unlock()
+
+	if deficit <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(time.Duration(deficit / b.rate * float64(time.Second)))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		//give back tokens of abandoned request
+		b.mu.Lock()
+		b.tokens += n
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// outcome is how an endpoint answered a request , it drives concurrency limit
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	outcomeThrottled
+)
+
+// aimdLimiter bounds in-flight requests of an endpoint . Limit grows by one after a full window
+// of successes and halves on 429 or timeout , like tcp congestion control.
+type aimdLimiter struct {
+	mu       sync.Mutex
+	limit    float64
+	min      float64
+	max      float64
+	inFlight int
+
+	// changed is closed and replaced whenever a slot is released
+	changed chan struct{}
+}
+
+func newAimdLimiter(min int, max int) *aimdLimiter {
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	return &aimdLimiter{limit: float64(min), min: float64(min), max: float64(max), changed: make(chan struct{})}
+}
+
+// acquire wait for a free slot
+func (l *aimdLimiter) acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.inFlight < int(l.limit) {
+			l.inFlight++
+			l.mu.Unlock()
+			return nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release free slot and adjust limit by outcome , other failures leave limit unchanged
+func (l *aimdLimiter) release(o outcome) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	switch o {
+	case outcomeSuccess:
+		l.limit += 1 / l.limit
+		if l.limit > l.max {
+			l.limit = l.max
+		}
+	case outcomeThrottled:
+		l.limit /= 2
+		if l.limit < l.min {
+			l.limit = l.min
+		}
+	}
+
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+func (l *aimdLimiter) stats() (int, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit), l.inFlight
+}
+
+// rpcMethod is the part of json-rpc request needed for pricing
+type rpcMethod struct {
+	Method string `json:"method"`
+}
+
+// requestCost returns number of calls and credits of json-rpc request body , batch is priced call by call
+func requestCost(body []byte, credits map[string]float64, defaultCredits float64) (float64, float64) {
+	var calls []rpcMethod
+	if err := json.Unmarshal(body, &calls); err != nil {
+		var call rpcMethod
+		if err := json.Unmarshal(body, &call); err != nil {
+			return 1, defaultCredits
+		}
+		calls = []rpcMethod{call}
+	}
+
+	var total float64
+	for _, call := range calls {
+		if c, exist := credits[call.Method]; exist {
+			total += c
+		} else {
+			total += defaultCredits
+		}
+	}
+	return float64(len(calls)), total
+}
@@ -0,0 +1,209 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewTokenBucket(t *testing.T) {
+	if b := newTokenBucket(0); b != nil {
+		t.Fatalf("rate 0 bucket = %+v , want nil", b)
+	}
+
+	//nil bucket is unlimited
+	var unlimited *tokenBucket
+	if err := unlimited.wait(context.Background(), 1e9); err != nil {
+		t.Fatal(err)
+	}
+
+	if b := newTokenBucket(0.5); b.burst != 1 || b.tokens != 1 {
+		t.Fatalf("rate 0.5 bucket burst %v tokens %v , want 1 and 1", b.burst, b.tokens)
+	}
+	if b := newTokenBucket(20); b.burst != 20 || b.tokens != 20 {
+		t.Fatalf("rate 20 bucket burst %v tokens %v , want 20 and 20", b.burst, b.tokens)
+	}
+}
+
+func TestTokenBucketWait(t *testing.T) {
+	ctx := context.Background()
+	b := newTokenBucket(100)
+
+	start := time.Now()
+	if err := b.wait(ctx, 100); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Fatalf("burst waited %v", elapsed)
+	}
+
+	//bucket is empty , 5 tokens refill in 50ms
+	start = time.Now()
+	if err := b.wait(ctx, 5); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("empty bucket waited %v , want about 50ms", elapsed)
+	}
+}
+
+func TestTokenBucketCostOverBurst(t *testing.T) {
+	b := newTokenBucket(100)
+
+	//a batch larger than burst passes once its 10 token deficit is refilled
+	start := time.Now()
+	if err := b.wait(context.Background(), 110); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Fatalf("cost over burst waited %v , want about 100ms", elapsed)
+	}
+}
+
+func TestTokenBucketCancel(t *testing.T) {
+	b := newTokenBucket(10)
+	if err := b.wait(context.Background(), 10); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.wait(ctx, 5); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v , want deadline exceeded", err)
+	}
+
+	//tokens of abandoned request are given back , so the next request does not pay for it
+	b.mu.Lock()
+	tokens := b.tokens
+	b.mu.Unlock()
+	if tokens < 0 {
+		t.Fatalf("tokens = %v after cancel , want refund", tokens)
+	}
+}
+
+func TestNewAimdLimiter(t *testing.T) {
+	cases := []struct {
+		min, max         int
+		wantMin, wantMax float64
+	}{
+		{0, 0, 1, 1},
+		{4, 2, 4, 4},
+		{2, 8, 2, 8},
+	}
+
+	for _, c := range cases {
+		l := newAimdLimiter(c.min, c.max)
+		if l.min != c.wantMin || l.max != c.wantMax || l.limit != c.wantMin {
+			t.Errorf("newAimdLimiter(%d, %d) = min %v max %v limit %v , want %v %v %v", c.min, c.max, l.min, l.max, l.limit, c.wantMin, c.wantMax, c.wantMin)
+		}
+	}
+}
+
+// settle acquire a slot and release it with outcome o
+func settle(t *testing.T, l *aimdLimiter, o outcome) {
+	t.Helper()
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	l.release(o)
+}
+
+func TestAimdLimiterAdjust(t *testing.T) {
+	l := newAimdLimiter(2, 4)
+
+	//limit grows by about one per window of successes
+	settle(t, l, outcomeSuccess)
+	settle(t, l, outcomeSuccess)
+	if limit, _ := l.stats(); limit != 2 {
+		t.Fatalf("limit = %d after 2 successes , want 2", limit)
+	}
+	settle(t, l, outcomeSuccess)
+	if limit, _ := l.stats(); limit != 3 {
+		t.Fatalf("limit = %d after 3 successes , want 3", limit)
+	}
+
+	for i := 0; i < 20; i++ {
+		settle(t, l, outcomeSuccess)
+	}
+	if limit, _ := l.stats(); limit != 4 {
+		t.Fatalf("limit = %d , want capped at 4", limit)
+	}
+
+	//plain failure is not a sign of overload
+	settle(t, l, outcomeFailure)
+	if limit, _ := l.stats(); limit != 4 {
+		t.Fatalf("limit = %d after failure , want 4", limit)
+	}
+
+	settle(t, l, outcomeThrottled)
+	if limit, _ := l.stats(); limit != 2 {
+		t.Fatalf("limit = %d after throttle , want 2", limit)
+	}
+	settle(t, l, outcomeThrottled)
+	if limit, inFlight := l.stats(); limit != 2 || inFlight != 0 {
+		t.Fatalf("limit %d in flight %d , want floor 2 and 0", limit, inFlight)
+	}
+}
+
+func TestAimdLimiterAcquire(t *testing.T) {
+	l := newAimdLimiter(1, 1)
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v , want deadline exceeded while slot is taken", err)
+	}
+
+	acquired := make(chan error)
+	go func() { acquired <- l.acquire(context.Background()) }()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquired before release")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	l.release(outcomeSuccess)
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiting acquire not woken by release")
+	}
+
+	if _, inFlight := l.stats(); inFlight != 1 {
+		t.Fatalf("in flight = %d , want 1", inFlight)
+	}
+}
+
+func TestRequestCost(t *testing.T) {
+	credits := map[string]float64{"eth_blockNumber": 10, "eth_getLogs": 75}
+
+	cases := []struct {
+		name        string
+		body        string
+		wantCalls   float64
+		wantCredits float64
+	}{
+		{"single call", `{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[]}`, 1, 75},
+		{"unlisted method", `{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`, 1, 2},
+		{"batch priced call by call", `[{"id":1,"method":"eth_blockNumber"},{"id":2,"method":"eth_getLogs"},{"id":3,"method":"eth_call"}]`, 3, 87},
+		{"empty batch", `[]`, 0, 0},
+		{"malformed body", `not json`, 1, 2},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			calls, total := requestCost([]byte(c.body), credits, 2)
+			if calls != c.wantCalls || total != c.wantCredits {
+				t.Fatalf("requestCost = %v , %v , want %v , %v", calls, total, c.wantCalls, c.wantCredits)
+			}
+		})
+	}
+}
@@ -0,0 +1,512 @@
+package ethclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/rs/zerolog/log"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StrategyRoundRobin   = "round_robin"
+	StrategyLeastLatency = "least_latency"
+)
+
+// latencyDecay is the weight of newest sample in endpoint latency moving average
+const latencyDecay = 0.2
+
+var errNoEndpoint = errors.New("no rpc endpoint configured")
+
+// PoolConfig is the routing , health check and ejection policy of a Pool
+type PoolConfig struct {
+	Strategy string
+
+	// HealthCheckInterval is the period of polling head height of every endpoint
+	HealthCheckInterval time.Duration
+
+	// MaxLagBlocks is how far an endpoint head may fall behind the highest head before it stops receiving requests
+	MaxLagBlocks uint64
+
+	// EjectErrors is the number of consecutive failures ejecting an endpoint , a 429 ejects immediately
+	EjectErrors int
+
+	// EjectDuration is how long an ejected endpoint is skipped
+	EjectDuration time.Duration
+
+	// RequestsPerSec and CreditsPerSec are token bucket rates of every endpoint , 0 means unlimited
+	RequestsPerSec float64
+	CreditsPerSec  float64
+
+	// MethodCredits is credit cost of json-rpc method , DefaultCredits is used for unlisted ones
+	MethodCredits  map[string]float64
+	DefaultCredits float64
+
+	// MinConcurrency and MaxConcurrency bound adaptive in-flight request limit of every endpoint
+	MinConcurrency int
+	MaxConcurrency int
+}
+
+// endpoint is one json-rpc provider of the pool with its health and stats
+type endpoint struct {
+	url    *url.URL
+	name   string
+	weight int
+
+	requestBucket *tokenBucket
+	creditBucket  *tokenBucket
+	limiter       *aimdLimiter
+
+	mu                sync.Mutex
+	currentWeight     int
+	headNum           uint64
+	lagging           bool
+	latency           time.Duration
+	consecutiveErrors int
+	ejectedUntil      time.Time
+
+	requests    uint64
+	failures    uint64
+	rateLimited uint64
+	ejections   uint64
+}
+
+// EndpointStats is the snapshot of an endpoint exposed through metrics
+type EndpointStats struct {
+	Endpoint          string  `json:"endpoint"`
+	Weight            int     `json:"weight"`
+	Available         bool    `json:"available"`
+	HeadNum           uint64  `json:"head_num"`
+	Lagging           bool    `json:"lagging"`
+	LatencyMs         float64 `json:"latency_ms"`
+	ConsecutiveErrors int     `json:"consecutive_errors"`
+	EjectedUntil      int64   `json:"ejected_until,omitempty"`
+	Requests          uint64  `json:"requests"`
+	Failures          uint64  `json:"failures"`
+	RateLimited       uint64  `json:"rate_limited"`
+	Ejections         uint64  `json:"ejections"`
+	Concurrency       int     `json:"concurrency"`
+	InFlight          int     `json:"in_flight"`
+}
+
+// Pool is a http.RoundTripper spreading json-rpc requests over several providers .
+// A failed request is retried on the next available endpoint , so callers only see an error when every endpoint failed.
+type Pool struct {
+	endpoints []*endpoint
+	cfg       PoolConfig
+	transport http.RoundTripper
+
+	// pickMu guards weighted round robin state of endpoints
+	pickMu sync.Mutex
+}
+
+// NewPool create pool over urls , weights[i] is the share of requests routed to urls[i] in round robin strategy
+func NewPool(urls []string, weights []int, cfg PoolConfig) (*Pool, error) {
+	if len(urls) == 0 {
+		return nil, errNoEndpoint
+	}
+
+	p := &Pool{cfg: cfg, transport: http.DefaultTransport}
+	for i, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		weight := 1
+		if i < len(weights) && weights[i] > 0 {
+			weight = weights[i]
+		}
+
+		//path and query usually carry api key , only host is exposed
+		p.endpoints = append(p.endpoints, &endpoint{
+			url:           u,
+			name:          fmt.Sprintf("%d:%s", i, u.Host),
+			weight:        weight,
+			requestBucket: newTokenBucket(cfg.RequestsPerSec),
+			creditBucket:  newTokenBucket(cfg.CreditsPerSec),
+			limiter:       newAimdLimiter(cfg.MinConcurrency, cfg.MaxConcurrency),
+		})
+	}
+
+	return p, nil
+}
+
+// URL returns url of first endpoint , the rpc client is dialed with it and pool rewrites every request
+func (p *Pool) URL() string {
+	return p.endpoints[0].url.String()
+}
+
+// RoundTrip send request to picked endpoint , failing over to the others on transport error , 429 or 5xx .
+// Every attempt waits for a concurrency slot and rate limit tokens of its endpoint.
+func (p *Pool) RoundTrip(req *http.Request) (*http.Response, error) {
+	calls, credits := float64(1), p.cfg.DefaultCredits
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		calls, credits = requestCost(data, p.cfg.MethodCredits, p.cfg.DefaultCredits)
+	}
+
+	tried := map[*endpoint]bool{}
+	var lastResp *http.Response
+	var lastErr error
+
+	for len(tried) < len(p.endpoints) {
+		ep := p.pick(tried)
+		tried[ep] = true
+
+		if lastResp != nil {
+			lastResp.Body.Close()
+			lastResp = nil
+		}
+
+		resp, err := p.send(req, ep, calls, credits)
+		if err == nil && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < http.StatusInternalServerError {
+			return resp, nil
+		}
+		lastResp, lastErr = resp, err
+
+		if req.Context().Err() != nil {
+			break
+		}
+		log.Debug().Str("endpoint", ep.name).Msg("rpc request fail , try next endpoint")
+	}
+
+	if lastResp != nil {
+		return lastResp, nil
+	}
+	return nil, lastErr
+}
+
+// send request to endpoint within its rate and concurrency limits , and record the outcome
+func (p *Pool) send(req *http.Request, ep *endpoint, calls float64, credits float64) (*http.Response, error) {
+	ctx := req.Context()
+	if err := ep.limiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := ep.requestBucket.wait(ctx, calls); err != nil {
+		ep.limiter.release(outcomeFailure)
+		return nil, err
+	}
+
+	if err := ep.creditBucket.wait(ctx, credits); err != nil {
+		ep.limiter.release(outcomeFailure)
+		return nil, err
+	}
+
+	r, err := p.rewrite(req, ep)
+	if err != nil {
+		ep.limiter.release(outcomeFailure)
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := p.transport.RoundTrip(r)
+	atomic.AddUint64(&ep.requests, 1)
+
+	switch {
+	case err != nil && ctx.Err() != nil:
+		//caller canceled or ran out of its own deadline , endpoint is not at fault
+		ep.limiter.release(outcomeFailure)
+	case err != nil && isTimeout(err):
+		p.fail(ep, false)
+		ep.limiter.release(outcomeThrottled)
+	case err != nil:
+		p.fail(ep, false)
+		ep.limiter.release(outcomeFailure)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		p.fail(ep, true)
+		ep.limiter.release(outcomeThrottled)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		p.fail(ep, false)
+		ep.limiter.release(outcomeFailure)
+	default:
+		p.succeed(ep, time.Since(start))
+		ep.limiter.release(outcomeSuccess)
+	}
+
+	return resp, err
+}
+
+// isTimeout reports whether request failed because endpoint answered too slowly
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// rewrite clone request for endpoint with a fresh body
+func (p *Pool) rewrite(req *http.Request, ep *endpoint) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	u := *ep.url
+	r.URL = &u
+	r.Host = ""
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+
+	return r, nil
+}
+
+// pick choose an untried endpoint by strategy among available ones ,
+// when all untried endpoints are ejected or lagging the one ejected earliest is used
+func (p *Pool) pick(tried map[*endpoint]bool) *endpoint {
+	p.pickMu.Lock()
+	defer p.pickMu.Unlock()
+
+	now := time.Now()
+	var candidates []*endpoint
+	var fallback *endpoint
+	var fallbackUntil time.Time
+	for _, ep := range p.endpoints {
+		if tried[ep] {
+			continue
+		}
+
+		ep.mu.Lock()
+		lagging, ejectedUntil := ep.lagging, ep.ejectedUntil
+		ep.mu.Unlock()
+
+		if !lagging && now.After(ejectedUntil) {
+			candidates = append(candidates, ep)
+		}
+		if fallback == nil || ejectedUntil.Before(fallbackUntil) {
+			fallback, fallbackUntil = ep, ejectedUntil
+		}
+	}
+
+	if len(candidates) == 0 {
+		return fallback
+	}
+
+	if p.cfg.Strategy == StrategyLeastLatency {
+		return leastLatency(candidates)
+	}
+	return smoothWeighted(candidates)
+}
+
+// smoothWeighted is nginx smooth weighted round robin , endpoints are interleaved by weight instead of bursts
+func smoothWeighted(candidates []*endpoint) *endpoint {
+	var best *endpoint
+	total := 0
+	for _, ep := range candidates {
+		ep.currentWeight += ep.weight
+		total += ep.weight
+		if best == nil || ep.currentWeight > best.currentWeight {
+			best = ep
+		}
+	}
+	best.currentWeight -= total
+	return best
+}
+
+// leastLatency returns endpoint with lowest latency average , endpoints without sample yet go first
+func leastLatency(candidates []*endpoint) *endpoint {
+	var best *endpoint
+	var bestLatency time.Duration
+	for _, ep := range candidates {
+		ep.mu.Lock()
+		latency := ep.latency
+		ep.mu.Unlock()
+
+		if best == nil || latency < bestLatency {
+			best, bestLatency = ep, latency
+		}
+	}
+	return best
+}
+
+func (p *Pool) succeed(ep *endpoint, latency time.Duration) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	ep.consecutiveErrors = 0
+	if ep.latency == 0 {
+		ep.latency = latency
+	} else {
+		ep.latency = time.Duration(latencyDecay*float64(latency) + (1-latencyDecay)*float64(ep.latency))
+	}
+}
+
+// fail count failure of endpoint and eject it after EjectErrors consecutive failures or a 429
+func (p *Pool) fail(ep *endpoint, rateLimited bool) {
+	atomic.AddUint64(&ep.failures, 1)
+	if rateLimited {
+		atomic.AddUint64(&ep.rateLimited, 1)
+	}
+
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	ep.consecutiveErrors++
+	if !rateLimited && ep.consecutiveErrors < p.cfg.EjectErrors {
+		return
+	}
+
+	if time.Now().After(ep.ejectedUntil) {
+		atomic.AddUint64(&ep.ejections, 1)
+		log.Warn().Str("endpoint", ep.name).Bool("rate_limited", rateLimited).Msg("eject rpc endpoint")
+	}
+	ep.ejectedUntil = time.Now().Add(p.cfg.EjectDuration)
+}
+
+// HealthCheck periodically poll head height of every endpoint until ctx is done
+func (p *Pool) HealthCheck(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		p.checkHeads(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			log.Print("break rpc health check loop")
+			return
+		}
+	}
+}
+
+// checkHeads fetch head of every endpoint and mark the ones behind highest head by more than MaxLagBlocks
+func (p *Pool) checkHeads(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, ep := range p.endpoints {
+		wg.Add(1)
+		go func(ep *endpoint) {
+			defer wg.Done()
+			headNum, err := p.fetchHead(ctx, ep)
+			if err != nil {
+				log.Debug().Err(err).Str("endpoint", ep.name).Msg("health check fail")
+				p.fail(ep, false)
+				return
+			}
+
+			ep.mu.Lock()
+			ep.headNum = headNum
+			ep.mu.Unlock()
+		}(ep)
+	}
+	wg.Wait()
+
+	var highest uint64
+	for _, ep := range p.endpoints {
+		ep.mu.Lock()
+		if ep.headNum > highest {
+			highest = ep.headNum
+		}
+		ep.mu.Unlock()
+	}
+
+	for _, ep := range p.endpoints {
+		ep.mu.Lock()
+		lagging := highest-ep.headNum > p.cfg.MaxLagBlocks
+		if lagging && !ep.lagging {
+			log.Warn().Str("endpoint", ep.name).Uint64("head_num", ep.headNum).Uint64("highest", highest).Msg("rpc endpoint lagging")
+		}
+		ep.lagging = lagging
+		ep.mu.Unlock()
+	}
+}
+
+// fetchHead call eth_blockNumber on endpoint directly , bypassing routing
+func (p *Pool) fetchHead(ctx context.Context, ep *endpoint) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.HealthCheckInterval)
+	defer cancel()
+
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+
+	//health check spends the same provider quota as routed requests
+	calls, credits := requestCost(body, p.cfg.MethodCredits, p.cfg.DefaultCredits)
+	if err := ep.requestBucket.wait(ctx, calls); err != nil {
+		return 0, err
+	}
+	if err := ep.creditBucket.wait(ctx, credits); err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.url.String(), bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.transport.RoundTrip(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("health check status %d", resp.StatusCode)
+	}
+
+	var res struct {
+		Result *hexutil.Uint64 `json:"result"`
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return 0, err
+	}
+	if res.Result == nil {
+		return 0, fmt.Errorf("health check no result: %s", data)
+	}
+
+	return uint64(*res.Result), nil
+}
+
+// Stats returns snapshot of every endpoint
+func (p *Pool) Stats() []EndpointStats {
+	now := time.Now()
+	res := make([]EndpointStats, 0, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		ep.mu.Lock()
+		stats := EndpointStats{
+			Endpoint:          ep.name,
+			Weight:            ep.weight,
+			Available:         !ep.lagging && now.After(ep.ejectedUntil),
+			HeadNum:           ep.headNum,
+			Lagging:           ep.lagging,
+			LatencyMs:         float64(ep.latency) / float64(time.Millisecond),
+			ConsecutiveErrors: ep.consecutiveErrors,
+		}
+		if now.Before(ep.ejectedUntil) {
+			stats.EjectedUntil = ep.ejectedUntil.UnixMilli()
+		}
+		ep.mu.Unlock()
+
+		stats.Requests = atomic.LoadUint64(&ep.requests)
+		stats.Failures = atomic.LoadUint64(&ep.failures)
+		stats.RateLimited = atomic.LoadUint64(&ep.rateLimited)
+		stats.Ejections = atomic.LoadUint64(&ep.ejections)
+		stats.Concurrency, stats.InFlight = ep.limiter.stats()
+		res = append(res, stats)
+	}
+	return res
+}
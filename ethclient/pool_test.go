@@ -0,0 +1,158 @@
+package ethclient
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewPool(t *testing.T) {
+	if _, err := NewPool(nil, nil, PoolConfig{}); err != errNoEndpoint {
+		t.Fatalf("err = %v , want %v", err, errNoEndpoint)
+	}
+
+	p, err := NewPool([]string{"https://mainnet.example.io/v3/secret", "http://127.0.0.1:8545"}, []int{3}, PoolConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//endpoint without weight gets 1 , api key in path is not exposed
+	stats := p.Stats()
+	if stats[0].Endpoint != "0:mainnet.example.io" || stats[0].Weight != 3 || stats[1].Endpoint != "1:127.0.0.1:8545" || stats[1].Weight != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func newTestPool(t *testing.T, weights []int) *Pool {
+	t.Helper()
+	urls := make([]string, len(weights))
+	for i := range weights {
+		urls[i] = "http://node" + string(rune('a'+i)) + ".local"
+	}
+
+	p, err := NewPool(urls, weights, PoolConfig{Strategy: StrategyRoundRobin, EjectErrors: 3, EjectDuration: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// pickSequence returns host letters of n endpoints picked for fresh requests
+func pickSequence(p *Pool, n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		sb.WriteByte(p.pick(map[*endpoint]bool{}).url.Host[4])
+	}
+	return sb.String()
+}
+
+func TestSmoothWeighted(t *testing.T) {
+	cases := []struct {
+		name    string
+		weights []int
+		want    string
+	}{
+		//heavier endpoint is interleaved with the others , not picked in a burst
+		{"nginx example", []int{5, 1, 1}, "aabacaa" + "aabacaa"},
+		{"equal weights", []int{1, 1, 1}, "abcabc"},
+		{"invalid weights default to 1", []int{2, 0, -1}, "abca" + "abca"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := newTestPool(t, c.weights)
+			if got := pickSequence(p, len(c.want)); got != c.want {
+				t.Fatalf("picked %s , want %s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestSmoothWeightedDistribution(t *testing.T) {
+	p := newTestPool(t, []int{3, 2, 5})
+
+	counts := map[byte]int{}
+	for _, host := range []byte(pickSequence(p, 1000)) {
+		counts[host]++
+	}
+
+	//every round of total weight picks each endpoint exactly weight times
+	if counts['a'] != 300 || counts['b'] != 200 || counts['c'] != 500 {
+		t.Fatalf("counts = a %d b %d c %d , want 300 200 500", counts['a'], counts['b'], counts['c'])
+	}
+}
+
+func TestPickSkipsUnavailable(t *testing.T) {
+	p := newTestPool(t, []int{1, 1, 1})
+
+	p.endpoints[0].ejectedUntil = time.Now().Add(time.Minute)
+	p.endpoints[1].lagging = true
+	if got := pickSequence(p, 3); got != "ccc" {
+		t.Fatalf("picked %s , want only available endpoint c", got)
+	}
+
+	//tried endpoints are excluded from failover
+	if ep := p.pick(map[*endpoint]bool{p.endpoints[2]: true}); ep != p.endpoints[1] {
+		t.Fatalf("picked %s , want endpoint ejected earliest", ep.name)
+	}
+
+	//with every endpoint unavailable the one ejected earliest is used
+	p.endpoints[2].ejectedUntil = time.Now().Add(2 * time.Minute)
+	if got := pickSequence(p, 1); got != "b" {
+		t.Fatalf("picked %s , want b which is lagging but not ejected", got)
+	}
+}
+
+// roundTripFunc is http.RoundTripper of a function
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// timeoutError is net.Error of a request the endpoint answered too slowly
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestSendCallerCanceled(t *testing.T) {
+	cases := []struct {
+		name         string
+		callerCancel bool
+		wantFailures uint64
+	}{
+		{"caller canceled", true, 0},
+		{"endpoint timeout", false, 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := newTestPool(t, []int{1})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			p.transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if c.callerCancel {
+					cancel()
+					return nil, ctx.Err()
+				}
+				return nil, timeoutError{}
+			})
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = p.send(req, p.endpoints[0], 1, 1); err == nil {
+				t.Fatal("send succeeded")
+			}
+
+			stats := p.Stats()[0]
+			if stats.Failures != c.wantFailures || stats.ConsecutiveErrors != int(c.wantFailures) || stats.InFlight != 0 {
+				t.Fatalf("stats = %+v , want %d failures and no request in flight", stats, c.wantFailures)
+			}
+		})
+	}
+}
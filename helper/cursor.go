@@ -0,0 +1,38 @@
+package helper
+
+import (
+	"encoding/base64"
+	"github.com/ryanCool/ethService/domain"
+	"strconv"
+	"strings"
+)
+
+// EncodeCursor encodes position of the last returned item into an opaque pagination cursor.
+func EncodeCursor(values ...uint64) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.FormatUint(v, 10)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(parts, ":")))
+}
+
+// DecodeCursor decodes cursor made by EncodeCursor , n is the expected number of values.
+func DecodeCursor(cursor string, n int) ([]uint64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != n {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	values := make([]uint64, n)
+	for i, part := range parts {
+		if values[i], err = strconv.ParseUint(part, 10, 64); err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+	}
+	return values, nil
+}
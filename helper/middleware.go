@@ -1,7 +1,9 @@
 package helper
 
 import (
+	"crypto/subtle"
 	"github.com/gin-gonic/gin"
+	"github.com/ryanCool/ethService/domain"
 	"net/http"
 )
 
@@ -19,3 +21,15 @@ func CorsMiddleware() gin.HandlerFunc {
 		}
 	}
 }
+
+// AdminAuthMiddleware allows request carrying `Authorization: Bearer <token>` only
+func AdminAuthMiddleware(token string) gin.HandlerFunc {
+	expected := []byte("Bearer " + token)
+	return func(c *gin.Context) {
+		if token == "" || subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), expected) != 1 {
+			RespondWithError(c, http.StatusUnauthorized, domain.ErrUnauthorized)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,29 @@
+package helper
+
+import (
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"strings"
+)
+
+// ParseBlockQuery parse optional block number query , decimal or 0x hex
+func ParseBlockQuery(ctx *gin.Context, key string) (*uint64, error) {
+	v := ctx.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+
+	n, err := ParseUint(v)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// ParseUint parse decimal number , or hex number with 0x prefix . Leading zeros are decimal , not octal.
+func ParseUint(v string) (uint64, error) {
+	if strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "0X") {
+		return strconv.ParseUint(v[2:], 16, 64)
+	}
+	return strconv.ParseUint(v, 10, 64)
+}
@@ -0,0 +1,48 @@
+package helper
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseUint(t *testing.T) {
+	cases := []struct {
+		v       string
+		want    uint64
+		wantErr bool
+	}{
+		{"16432462", 16432462, false},
+		{"010", 10, false},
+		{"0", 0, false},
+		{"0xfaa3ce", 16425934, false},
+		{"0XFF", 255, false},
+		{"0x", 0, true},
+		{"0b101", 0, true},
+		{"0o17", 0, true},
+		{"1_000", 0, true},
+		{"-1", 0, true},
+		{"latest", 0, true},
+	}
+
+	for _, c := range cases {
+		got, err := ParseUint(c.v)
+		if (err != nil) != c.wantErr || got != c.want {
+			t.Errorf("ParseUint(%q) = %d , %v , want %d , error %v", c.v, got, err, c.want, c.wantErr)
+		}
+	}
+}
+
+func TestParseBlockQuery(t *testing.T) {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("GET", "/logs?from_block=010", nil)
+
+	n, err := ParseBlockQuery(ctx, "from_block")
+	if err != nil || n == nil || *n != 10 {
+		t.Fatalf("from_block = %v , %v , want 10", n, err)
+	}
+
+	if n, err = ParseBlockQuery(ctx, "to_block"); err != nil || n != nil {
+		t.Fatalf("absent to_block = %v , %v , want nil", n, err)
+	}
+}
@@ -0,0 +1,245 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ryanCool/ethService/domain"
+	"math/big"
+)
+
+// maxLogResults is the max number of logs returned by one eth_getLogs call
+const maxLogResults = 10000
+
+// EthAPI answers eth namespace methods from indexed db , unknown block or transaction returns null like a node does
+type EthAPI struct {
+	bUseCase      domain.BlockUseCase
+	tUseCase      domain.TransactionUseCase
+	maxBlockRange uint64
+}
+
+func NewEthAPI(db domain.BlockUseCase, dt domain.TransactionUseCase, maxBlockRange uint64) *EthAPI {
+	return &EthAPI{
+		bUseCase:      db,
+		tUseCase:      dt,
+		maxBlockRange: maxBlockRange,
+	}
+}
+
+// BlockNumber returns the latest indexed block number
+func (api *EthAPI) BlockNumber(ctx context.Context) (hexutil.Uint64, error) {
+	latestNum, err := api.latestNum(ctx)
+	return hexutil.Uint64(latestNum), err
+}
+
+func (api *EthAPI) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, fullTx bool) (*rpcBlock, error) {
+	blockNum, err := api.resolveBlockNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := api.bUseCase.GetByNumber(ctx, blockNum)
+	if err == domain.ErrBlockNotExist {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return api.toRPCBlock(ctx, block, fullTx)
+}
+
+func (api *EthAPI) GetBlockByHash(ctx context.Context, hash common.Hash, fullTx bool) (*rpcBlock, error) {
+	block, err := api.bUseCase.GetByHash(ctx, hash.String())
+	if err == domain.ErrBlockNotExist {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return api.toRPCBlock(ctx, block, fullTx)
+}
+
+// GetUncleByBlockHashAndIndex returns uncle header as node reported it when the block was indexed
+func (api *EthAPI) GetUncleByBlockHashAndIndex(ctx context.Context, hash common.Hash, index hexutil.Uint) (json.RawMessage, error) {
+	block, err := api.bUseCase.GetByHash(ctx, hash.String())
+	if err == domain.ErrBlockNotExist {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if int(index) >= len(block.Uncles) {
+		return nil, nil
+	}
+
+	uncle := block.Uncles[index]
+	if len(uncle.Header) == 0 {
+		return nil, fmt.Errorf("header of uncle %d not indexed , block %s was saved before uncle headers were stored", index, hash)
+	}
+	return uncle.Header, nil
+}
+
+func (api *EthAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) (*rpcTransaction, error) {
+	transaction, err := api.tUseCase.GetByTxHash(ctx, hash.String())
+	if err == domain.ErrTransactionNotExist {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toRPCTransaction(transaction), nil
+}
+
+func (api *EthAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*rpcReceipt, error) {
+	transaction, err := api.tUseCase.GetByTxHash(ctx, hash.String())
+	if err == domain.ErrTransactionNotExist {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	//receipt not written yet , node also returns null for it
+	if transaction.Receipt == nil {
+		return nil, nil
+	}
+
+	return toRPCReceipt(transaction), nil
+}
+
+func (api *EthAPI) GetLogs(ctx context.Context, crit filterCriteria) ([]*types.Log, error) {
+	filter := &domain.LogFilter{Limit: maxLogResults + 1}
+	if crit.BlockHash != nil {
+		if crit.FromBlock != nil || crit.ToBlock != nil {
+			return nil, errors.New("cannot specify both BlockHash and FromBlock/ToBlock, choose one or the other")
+		}
+
+		block, err := api.bUseCase.GetByHash(ctx, crit.BlockHash.String())
+		if err == domain.ErrBlockNotExist {
+			return nil, errors.New("unknown block")
+		}
+		if err != nil {
+			return nil, err
+		}
+		filter.FromBlock, filter.ToBlock = block.BlockNum, block.BlockNum
+	} else {
+		var err error
+		if filter.FromBlock, err = api.resolveOptionalBlockNumber(ctx, crit.FromBlock); err != nil {
+			return nil, err
+		}
+		if filter.ToBlock, err = api.resolveOptionalBlockNumber(ctx, crit.ToBlock); err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.ToBlock >= filter.FromBlock && filter.ToBlock-filter.FromBlock >= api.maxBlockRange {
+		return nil, domain.ErrBlockRangeTooLarge
+	}
+
+	for _, address := range crit.Addresses {
+		filter.Addresses = append(filter.Addresses, address.String())
+	}
+	for _, set := range crit.Topics {
+		var topics []string
+		for _, topic := range set {
+			topics = append(topics, topic.String())
+		}
+		filter.Topics = append(filter.Topics, topics)
+	}
+
+	logs, err := api.tUseCase.FilterLogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(logs) > maxLogResults {
+		return nil, fmt.Errorf("query returned more than %d results", maxLogResults)
+	}
+
+	res := make([]*types.Log, 0, len(logs))
+	for i := range logs {
+		res = append(res, toRPCLog(&logs[i]))
+	}
+	return res, nil
+}
+
+func (api *EthAPI) latestNum(ctx context.Context) (uint64, error) {
+	latest, err := api.bUseCase.List(ctx, domain.NewLatestBlockFilter())
+	if err != nil {
+		return 0, err
+	}
+
+	if len(latest) == 0 {
+		return 0, nil
+	}
+	return latest[0].BlockNum, nil
+}
+
+// resolveBlockNumber turns block number or tag to a stored block number
+func (api *EthAPI) resolveBlockNumber(ctx context.Context, number rpc.BlockNumber) (uint64, error) {
+	switch number {
+	case rpc.LatestBlockNumber, rpc.PendingBlockNumber:
+		return api.latestNum(ctx)
+	case rpc.EarliestBlockNumber:
+		return 0, nil
+	case rpc.SafeBlockNumber, rpc.FinalizedBlockNumber:
+		tag := domain.BlockTagSafe
+		if number == rpc.FinalizedBlockNumber {
+			tag = domain.BlockTagFinalized
+		}
+
+		block, err := api.bUseCase.GetByTag(ctx, tag)
+		if err != nil {
+			return 0, err
+		}
+		return block.BlockNum, nil
+	}
+
+	return uint64(number.Int64()), nil
+}
+
+// resolveOptionalBlockNumber resolve filter bound , omitted one means latest
+func (api *EthAPI) resolveOptionalBlockNumber(ctx context.Context, number *rpc.BlockNumber) (uint64, error) {
+	if number == nil {
+		return api.latestNum(ctx)
+	}
+	return api.resolveBlockNumber(ctx, *number)
+}
+
+func (api *EthAPI) toRPCBlock(ctx context.Context, block *domain.Block, fullTx bool) (*rpcBlock, error) {
+	res := newRPCBlock(block)
+	if !fullTx {
+		for _, hash := range block.TransactionHashes {
+			res.Transactions = append(res.Transactions, common.HexToHash(hash))
+		}
+		return res, nil
+	}
+
+	transactions, err := api.tUseCase.ListByBlockHash(ctx, block.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range transactions {
+		res.Transactions = append(res.Transactions, toRPCTransaction(&transactions[i]))
+	}
+	return res, nil
+}
+
+// decimalToBig parse stored decimal amount , malformed one is treated as zero
+func decimalToBig(v string) *hexutil.Big {
+	n, ok := new(big.Int).SetString(v, 10)
+	if !ok {
+		n = new(big.Int)
+	}
+	return (*hexutil.Big)(n)
+}
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ryanCool/ethService/domain"
+	"math/big"
+	"testing"
+)
+
+// londonHeader is a pre-Shanghai header with every field set , its hash is computed by types.Header
+func londonHeader() *types.Header {
+	return &types.Header{
+		ParentHash:  common.HexToHash("0x01"),
+		UncleHash:   common.HexToHash("0x02"),
+		Coinbase:    common.HexToAddress("0x03"),
+		Root:        common.HexToHash("0x04"),
+		TxHash:      common.HexToHash("0x05"),
+		ReceiptHash: common.HexToHash("0x06"),
+		Bloom:       types.BytesToBloom([]byte{0x07, 0x08}),
+		Difficulty:  big.NewInt(9),
+		Number:      big.NewInt(15000000),
+		GasLimit:    30000000,
+		GasUsed:     12000000,
+		Time:        1651000000,
+		Extra:       []byte("extra"),
+		MixDigest:   common.HexToHash("0x0a"),
+		Nonce:       types.EncodeNonce(0x0b0c),
+		BaseFee:     big.NewInt(13),
+	}
+}
+
+func TestNewRPCBlockHeaderHash(t *testing.T) {
+	header := londonHeader()
+	block := &domain.Block{BlockDb: domain.BlockDb{
+		BlockNum:         header.Number.Uint64(),
+		BlockHash:        header.Hash().String(),
+		BlockTime:        header.Time,
+		ParentHash:       header.ParentHash.String(),
+		Miner:            header.Coinbase.String(),
+		GasLimit:         header.GasLimit,
+		GasUsed:          header.GasUsed,
+		BaseFeePerGas:    header.BaseFee.String(),
+		Difficulty:       header.Difficulty.String(),
+		ExtraData:        header.Extra,
+		StateRoot:        header.Root.String(),
+		TransactionsRoot: header.TxHash.String(),
+		ReceiptsRoot:     header.ReceiptHash.String(),
+		Sha3Uncles:       header.UncleHash.String(),
+		Nonce:            hexutil.Encode(header.Nonce[:]),
+		MixHash:          header.MixDigest.String(),
+		LogsBloom:        header.Bloom.Bytes(),
+	}}
+
+	data, err := json.Marshal(newRPCBlock(block))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded *types.Header
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Hash() != header.Hash() {
+		t.Fatalf("client computed hash %s , want %s\n%s", decoded.Hash(), header.Hash(), data)
+	}
+}
+
+// unclesBlocks is block use case holding one block with uncles
+type unclesBlocks struct {
+	domain.BlockUseCase
+	block *domain.Block
+}
+
+func (u *unclesBlocks) GetByHash(ctx context.Context, blockHash string) (*domain.Block, error) {
+	if blockHash != u.block.BlockHash {
+		return nil, domain.ErrBlockNotExist
+	}
+	return u.block, nil
+}
+
+func TestGetUncleByBlockHashAndIndex(t *testing.T) {
+	blockHash := common.HexToHash("0xaa")
+	uncleHeader := json.RawMessage(`{"hash":"0x00000000000000000000000000000000000000000000000000000000000000bb","number":"0x1"}`)
+	api := NewEthAPI(&unclesBlocks{block: &domain.Block{BlockDb: domain.BlockDb{
+		BlockHash: blockHash.String(),
+		Uncles: []domain.Uncle{
+			{UncleIndex: 0, Header: uncleHeader},
+			{UncleIndex: 1},
+		},
+	}}}, nil, 0)
+
+	cases := []struct {
+		name    string
+		hash    common.Hash
+		index   hexutil.Uint
+		want    json.RawMessage
+		wantErr bool
+	}{
+		{"stored header", blockHash, 0, uncleHeader, false},
+		{"header not stored", blockHash, 1, nil, true},
+		{"index out of range", blockHash, 2, nil, false},
+		{"unknown block", common.HexToHash("0xcc"), 0, nil, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := api.GetUncleByBlockHashAndIndex(context.Background(), c.hash, c.index)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("err %v , want error %v", err, c.wantErr)
+			}
+			if string(got) != string(c.want) {
+				t.Fatalf("uncle %s , want %s", got, c.want)
+			}
+		})
+	}
+}
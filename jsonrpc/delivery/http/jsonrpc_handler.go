@@ -0,0 +1,17 @@
+package http
+
+import (
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gin-gonic/gin"
+	"github.com/ryanCool/ethService/domain"
+)
+
+// NewJsonRpcHandler serve Ethereum JSON-RPC 2.0 read methods (single and batch request) over indexed db
+func NewJsonRpcHandler(e *gin.Engine, db domain.BlockUseCase, dt domain.TransactionUseCase, maxBlockRange uint64) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", NewEthAPI(db, dt, maxBlockRange)); err != nil {
+		panic(err)
+	}
+
+	e.POST("/rpc", gin.WrapH(server))
+}
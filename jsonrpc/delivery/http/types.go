@@ -0,0 +1,324 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ryanCool/ethService/domain"
+	"math/big"
+)
+
+// rpcBlock is the block object of eth_getBlockBy* , header fields of blocks indexed before they were stored are returned as zero value
+type rpcBlock struct {
+	Number           hexutil.Uint64   `json:"number"`
+	Hash             common.Hash      `json:"hash"`
+	ParentHash       common.Hash      `json:"parentHash"`
+	Nonce            types.BlockNonce `json:"nonce"`
+	MixHash          common.Hash      `json:"mixHash"`
+	Sha3Uncles       common.Hash      `json:"sha3Uncles"`
+	LogsBloom        types.Bloom      `json:"logsBloom"`
+	StateRoot        common.Hash      `json:"stateRoot"`
+	Miner            common.Address   `json:"miner"`
+	Difficulty       *hexutil.Big     `json:"difficulty"`
+	ExtraData        hexutil.Bytes    `json:"extraData"`
+	GasLimit         hexutil.Uint64   `json:"gasLimit"`
+	GasUsed          hexutil.Uint64   `json:"gasUsed"`
+	Timestamp        hexutil.Uint64   `json:"timestamp"`
+	TransactionsRoot common.Hash      `json:"transactionsRoot"`
+	ReceiptsRoot     common.Hash      `json:"receiptsRoot"`
+	Transactions     []interface{}    `json:"transactions"`
+	Uncles           []common.Hash    `json:"uncles"`
+
+	BaseFee         *hexutil.Big    `json:"baseFeePerGas,omitempty"`
+	Size            hexutil.Uint64  `json:"size"`
+	TotalDifficulty *hexutil.Big    `json:"totalDifficulty,omitempty"`
+	WithdrawalsRoot *common.Hash    `json:"withdrawalsRoot,omitempty"`
+	Withdrawals     []rpcWithdrawal `json:"withdrawals,omitempty"`
+}
+
+type rpcWithdrawal struct {
+	Index     hexutil.Uint64 `json:"index"`
+	Validator hexutil.Uint64 `json:"validatorIndex"`
+	Address   common.Address `json:"address"`
+	Amount    hexutil.Uint64 `json:"amount"`
+}
+
+type rpcTransaction struct {
+	BlockHash        *common.Hash    `json:"blockHash"`
+	BlockNumber      *hexutil.Big    `json:"blockNumber"`
+	From             common.Address  `json:"from"`
+	Gas              hexutil.Uint64  `json:"gas"`
+	GasPrice         *hexutil.Big    `json:"gasPrice"`
+	Hash             common.Hash     `json:"hash"`
+	Input            hexutil.Bytes   `json:"input"`
+	Nonce            hexutil.Uint64  `json:"nonce"`
+	To               *common.Address `json:"to"`
+	TransactionIndex *hexutil.Uint64 `json:"transactionIndex"`
+	Value            *hexutil.Big    `json:"value"`
+	Type             hexutil.Uint64  `json:"type"`
+	V                *hexutil.Big    `json:"v"`
+	R                *hexutil.Big    `json:"r"`
+	S                *hexutil.Big    `json:"s"`
+
+	ChainID   *hexutil.Big      `json:"chainId,omitempty"`
+	GasFeeCap *hexutil.Big      `json:"maxFeePerGas,omitempty"`
+	GasTipCap *hexutil.Big      `json:"maxPriorityFeePerGas,omitempty"`
+	Accesses  *types.AccessList `json:"accessList,omitempty"`
+}
+
+type rpcReceipt struct {
+	BlockHash         common.Hash     `json:"blockHash"`
+	BlockNumber       hexutil.Uint64  `json:"blockNumber"`
+	TransactionHash   common.Hash     `json:"transactionHash"`
+	TransactionIndex  hexutil.Uint64  `json:"transactionIndex"`
+	From              common.Address  `json:"from"`
+	To                *common.Address `json:"to"`
+	GasUsed           hexutil.Uint64  `json:"gasUsed"`
+	CumulativeGasUsed hexutil.Uint64  `json:"cumulativeGasUsed"`
+	EffectiveGasPrice *hexutil.Big    `json:"effectiveGasPrice"`
+	ContractAddress   *common.Address `json:"contractAddress"`
+	Logs              []*types.Log    `json:"logs"`
+	LogsBloom         types.Bloom     `json:"logsBloom"`
+	Type              hexutil.Uint64  `json:"type"`
+	Status            hexutil.Uint64  `json:"status"`
+}
+
+// filterCriteria is the eth_getLogs argument , address accepts one address or a list ,
+// topics accept null , one topic or a list of topics per position
+type filterCriteria struct {
+	BlockHash *common.Hash
+	FromBlock *rpc.BlockNumber
+	ToBlock   *rpc.BlockNumber
+	Addresses []common.Address
+	Topics    [][]common.Hash
+}
+
+func (fc *filterCriteria) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		BlockHash *common.Hash      `json:"blockHash"`
+		FromBlock *rpc.BlockNumber  `json:"fromBlock"`
+		ToBlock   *rpc.BlockNumber  `json:"toBlock"`
+		Addresses json.RawMessage   `json:"address"`
+		Topics    []json.RawMessage `json:"topics"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	fc.BlockHash, fc.FromBlock, fc.ToBlock = raw.BlockHash, raw.FromBlock, raw.ToBlock
+
+	if len(raw.Addresses) > 0 && string(raw.Addresses) != "null" {
+		var address common.Address
+		if err := json.Unmarshal(raw.Addresses, &fc.Addresses); err != nil {
+			if err = json.Unmarshal(raw.Addresses, &address); err != nil {
+				return errors.New("invalid addresses in query")
+			}
+			fc.Addresses = []common.Address{address}
+		}
+	}
+
+	if len(raw.Topics) > domain.MaxLogTopics {
+		return errors.New("too many topics in query")
+	}
+	for _, rawTopic := range raw.Topics {
+		fc.Topics = append(fc.Topics, nil)
+		if string(rawTopic) == "null" {
+			continue
+		}
+
+		var topic common.Hash
+		if err := json.Unmarshal(rawTopic, &topic); err == nil {
+			fc.Topics[len(fc.Topics)-1] = []common.Hash{topic}
+			continue
+		}
+
+		var topics []*common.Hash
+		if err := json.Unmarshal(rawTopic, &topics); err != nil {
+			return errors.New("invalid topic(s)")
+		}
+
+		//null inside a set matches anything at this position
+		var set []common.Hash
+		for _, t := range topics {
+			if t == nil {
+				set = nil
+				break
+			}
+			set = append(set, *t)
+		}
+		fc.Topics[len(fc.Topics)-1] = set
+	}
+
+	return nil
+}
+
+func newRPCBlock(block *domain.Block) *rpcBlock {
+	res := &rpcBlock{
+		Number:           hexutil.Uint64(block.BlockNum),
+		Hash:             common.HexToHash(block.BlockHash),
+		ParentHash:       common.HexToHash(block.ParentHash),
+		Sha3Uncles:       types.EmptyUncleHash,
+		StateRoot:        common.HexToHash(block.StateRoot),
+		Miner:            common.HexToAddress(block.Miner),
+		Difficulty:       decimalToBig(block.Difficulty),
+		ExtraData:        hexutil.Bytes{},
+		GasLimit:         hexutil.Uint64(block.GasLimit),
+		GasUsed:          hexutil.Uint64(block.GasUsed),
+		Timestamp:        hexutil.Uint64(block.BlockTime),
+		TransactionsRoot: common.HexToHash(block.TransactionsRoot),
+		ReceiptsRoot:     common.HexToHash(block.ReceiptsRoot),
+		Transactions:     []interface{}{},
+		Uncles:           []common.Hash{},
+		Size:             hexutil.Uint64(block.Size),
+	}
+
+	if block.Sha3Uncles != "" {
+		res.Sha3Uncles = common.HexToHash(block.Sha3Uncles)
+	}
+
+	copy(res.Nonce[:], common.FromHex(block.Nonce))
+	res.MixHash = common.HexToHash(block.MixHash)
+	res.LogsBloom = types.BytesToBloom(block.LogsBloom)
+
+	for _, uncle := range block.Uncles {
+		res.Uncles = append(res.Uncles, common.HexToHash(uncle.UncleHash))
+	}
+
+	if len(block.ExtraData) > 0 {
+		res.ExtraData = block.ExtraData
+	}
+
+	if block.BaseFeePerGas != "" {
+		res.BaseFee = decimalToBig(block.BaseFeePerGas)
+	}
+
+	if block.TotalDifficulty != "" {
+		res.TotalDifficulty = decimalToBig(block.TotalDifficulty)
+	}
+
+	if block.WithdrawalsRoot != "" {
+		root := common.HexToHash(block.WithdrawalsRoot)
+		res.WithdrawalsRoot = &root
+		res.Withdrawals = []rpcWithdrawal{}
+	}
+
+	for _, w := range block.Withdrawals {
+		res.Withdrawals = append(res.Withdrawals, rpcWithdrawal{
+			Index:     hexutil.Uint64(w.WithdrawalIndex),
+			Validator: hexutil.Uint64(w.ValidatorIndex),
+			Address:   common.HexToAddress(w.Address),
+			Amount:    hexutil.Uint64(w.Amount),
+		})
+	}
+
+	//blocks indexed before header fields were stored have no roots , clients check transactions against root
+	if block.TxCount == 0 && block.TransactionsRoot == "" {
+		res.TransactionsRoot = types.EmptyRootHash
+		res.ReceiptsRoot = types.EmptyRootHash
+	}
+
+	return res
+}
+
+func toRPCTransaction(transaction *domain.Transaction) *rpcTransaction {
+	blockHash := common.HexToHash(transaction.BlockHash)
+	txIndex := hexutil.Uint64(transaction.TxIndex)
+	res := &rpcTransaction{
+		BlockHash:        &blockHash,
+		BlockNumber:      (*hexutil.Big)(new(big.Int).SetUint64(transaction.BlockNum)),
+		From:             common.HexToAddress(transaction.TxFrom),
+		Gas:              hexutil.Uint64(transaction.Gas),
+		GasPrice:         decimalToBig(transaction.GasPrice),
+		Hash:             common.HexToHash(transaction.TxHash),
+		Input:            transaction.TxData,
+		Nonce:            hexutil.Uint64(transaction.Nonce),
+		To:               toAddress(transaction.TxTo),
+		TransactionIndex: &txIndex,
+		Value:            decimalToBig(transaction.TxValue),
+		Type:             hexutil.Uint64(transaction.TxType),
+		V:                decimalToBig(transaction.V),
+		R:                decimalToBig(transaction.R),
+		S:                decimalToBig(transaction.S),
+	}
+
+	//typed transaction carries chain id and access list
+	if transaction.TxType != types.LegacyTxType {
+		accessList := types.AccessList{}
+		for _, entry := range transaction.AccessList {
+			tuple := types.AccessTuple{Address: common.HexToAddress(entry.Address), StorageKeys: []common.Hash{}}
+			for _, key := range entry.StorageKeys {
+				tuple.StorageKeys = append(tuple.StorageKeys, common.HexToHash(key))
+			}
+			accessList = append(accessList, tuple)
+		}
+		res.ChainID = decimalToBig(transaction.ChainID)
+		res.Accesses = &accessList
+	}
+
+	if transaction.TxType == types.DynamicFeeTxType {
+		res.GasFeeCap = decimalToBig(transaction.MaxFeePerGas)
+		res.GasTipCap = decimalToBig(transaction.MaxPriorityFeePerGas)
+	}
+
+	return res
+}
+
+func toRPCReceipt(transaction *domain.Transaction) *rpcReceipt {
+	receipt := transaction.Receipt
+	res := &rpcReceipt{
+		BlockHash:         common.HexToHash(transaction.BlockHash),
+		BlockNumber:       hexutil.Uint64(transaction.BlockNum),
+		TransactionHash:   common.HexToHash(transaction.TxHash),
+		TransactionIndex:  hexutil.Uint64(transaction.TxIndex),
+		From:              common.HexToAddress(transaction.TxFrom),
+		To:                toAddress(transaction.TxTo),
+		GasUsed:           hexutil.Uint64(receipt.GasUsed),
+		CumulativeGasUsed: hexutil.Uint64(receipt.CumulativeGasUsed),
+		EffectiveGasPrice: decimalToBig(receipt.EffectiveGasPrice),
+		ContractAddress:   toAddress(receipt.ContractAddress),
+		Logs:              []*types.Log{},
+		LogsBloom:         types.BytesToBloom(receipt.LogsBloom),
+		Type:              hexutil.Uint64(receipt.TxType),
+		Status:            hexutil.Uint64(receipt.Status),
+	}
+
+	for i := range transaction.Logs {
+		res.Logs = append(res.Logs, toRPCLog(&transaction.Logs[i]))
+	}
+
+	return res
+}
+
+func toRPCLog(l *domain.TransactionLog) *types.Log {
+	res := &types.Log{
+		Address:     common.HexToAddress(l.Address),
+		Topics:      []common.Hash{},
+		Data:        l.LogData,
+		BlockNumber: l.BlockNum,
+		TxHash:      common.HexToHash(l.TxHash),
+		TxIndex:     l.TxIndex,
+		BlockHash:   common.HexToHash(l.BlockHash),
+		Index:       uint(l.LogIndex),
+	}
+
+	for _, topic := range []string{l.Topic0, l.Topic1, l.Topic2, l.Topic3} {
+		if topic == "" {
+			break
+		}
+		res.Topics = append(res.Topics, common.HexToHash(topic))
+	}
+
+	return res
+}
+
+// toAddress returns nil for empty or zero address , which stands for contract creation
+func toAddress(address string) *common.Address {
+	a := common.HexToAddress(address)
+	if address == "" || a == (common.Address{}) {
+		return nil
+	}
+	return &a
+}
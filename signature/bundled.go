@@ -0,0 +1,10 @@
+package signature
+
+import (
+	_ "embed"
+)
+
+// Bundled is the signature table shipped with the service , in text dump format
+//
+//go:embed signatures.txt
+var Bundled []byte
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gin-gonic/gin"
+	"github.com/ryanCool/ethService/domain"
+	"github.com/ryanCool/ethService/helper"
+	"net/http"
+)
+
+// SignatureHandler represent the httphandler for function and event signature table
+type SignatureHandler struct {
+	SUseCase domain.SignatureUseCase
+}
+
+// addSignaturesRequest is the text signatures of one kind to add
+type addSignaturesRequest struct {
+	Kind       string   `json:"kind"`
+	Signatures []string `json:"signatures"`
+}
+
+func NewSignatureHandler(e *gin.Engine, su domain.SignatureUseCase, adminToken string) {
+	handler := &SignatureHandler{
+		SUseCase: su,
+	}
+
+	sg := e.Group("signatures")
+
+	sg.GET("/:hash", handler.ListByHash)
+	sg.POST("/", helper.AdminAuthMiddleware(adminToken), handler.AddSignatures)
+}
+
+// ListByHash list text signatures of 4-byte selector or 32-byte event topic
+func (a *SignatureHandler) ListByHash(ctx *gin.Context) {
+	b, err := hexutil.Decode(ctx.Param("hash"))
+	if err != nil || (len(b) != 4 && len(b) != common.HashLength) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidSignature)
+		return
+	}
+
+	signatures, err := a.SUseCase.ListByHash(ctx, hexutil.Encode(b))
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{"signatures": signatures})
+}
+
+// AddSignatures hash and save text signatures , existing ones are kept
+func (a *SignatureHandler) AddSignatures(ctx *gin.Context) {
+	req := &addSignaturesRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	signatures, err := a.SUseCase.Add(ctx, req.Kind, req.Signatures)
+	if err == domain.ErrInvalidSignature {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{"signatures": signatures})
+}
@@ -0,0 +1,128 @@
+package signature
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ryanCool/ethService/domain"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// KindOfHash derive signature kind from hash length , 4 bytes for function and 32 bytes for event
+func KindOfHash(hash string) (string, error) {
+	b, err := hexutil.Decode(hash)
+	if err != nil {
+		return "", domain.ErrInvalidSignature
+	}
+
+	switch len(b) {
+	case 4:
+		return domain.SignatureKindFunction, nil
+	case common.HashLength:
+		return domain.SignatureKindEvent, nil
+	}
+	return "", domain.ErrInvalidSignature
+}
+
+// ParseText parse text dump , each line is "<hash> <text signature>" or a bare text signature of defaultKind .
+// Blank lines and lines starting with # are skipped.
+func ParseText(r io.Reader, defaultKind string) ([]domain.Signature, error) {
+	var res []domain.Signature
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		switch {
+		case len(fields) == 2 && strings.HasPrefix(fields[0], "0x"):
+			kind, err := KindOfHash(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			res = append(res, domain.Signature{Hash: strings.ToLower(fields[0]), Kind: kind, TextSignature: fields[1]})
+		case defaultKind != "":
+			res = append(res, domain.Signature{Kind: defaultKind, TextSignature: strings.Join(fields, "")})
+		default:
+			return nil, fmt.Errorf("line %d: signature kind unknown", line)
+		}
+	}
+
+	return res, scanner.Err()
+}
+
+// fourByteSignature is one entry of 4byte.directory api dump
+type fourByteSignature struct {
+	HexSignature  string `json:"hex_signature"`
+	TextSignature string `json:"text_signature"`
+}
+
+// ParseJSON parse 4byte.directory dump , either its paged api response or a plain array of results
+func ParseJSON(r io.Reader) ([]domain.Signature, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []fourByteSignature
+	if err := json.Unmarshal(data, &entries); err != nil {
+		page := struct {
+			Results []fourByteSignature `json:"results"`
+		}{}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, err
+		}
+		entries = page.Results
+	}
+
+	res := make([]domain.Signature, 0, len(entries))
+	for _, entry := range entries {
+		kind, err := KindOfHash(entry.HexSignature)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.HexSignature, err)
+		}
+		res = append(res, domain.Signature{Hash: strings.ToLower(entry.HexSignature), Kind: kind, TextSignature: entry.TextSignature})
+	}
+	return res, nil
+}
+
+// ParseDir parse ethereum-lists/4bytes style directory , file name is the hash without 0x
+// and content is one or more text signatures separated by ;
+func ParseDir(dir string) ([]domain.Signature, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []domain.Signature
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		hash := "0x" + strings.ToLower(file.Name())
+		kind, err := KindOfHash(hash)
+		if err != nil {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, text := range strings.Split(string(content), ";") {
+			if text = strings.TrimSpace(text); text != "" {
+				res = append(res, domain.Signature{Hash: hash, Kind: kind, TextSignature: text})
+			}
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,143 @@
+package signature
+
+import (
+	"errors"
+	"github.com/ryanCool/ethService/domain"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	transferHash = "0xa9059cbb"
+	transferText = "transfer(address,uint256)"
+	transferLog  = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+)
+
+func openFixture(t *testing.T, name string) *os.File {
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestKindOfHash(t *testing.T) {
+	cases := []struct {
+		hash string
+		kind string
+		err  error
+	}{
+		{transferHash, domain.SignatureKindFunction, nil},
+		{transferLog, domain.SignatureKindEvent, nil},
+		{"0xa9059c", "", domain.ErrInvalidSignature},
+		{"a9059cbb", "", domain.ErrInvalidSignature},
+		{"0xzz059cbb", "", domain.ErrInvalidSignature},
+	}
+
+	for _, c := range cases {
+		kind, err := KindOfHash(c.hash)
+		if kind != c.kind || err != c.err {
+			t.Errorf("KindOfHash(%s) = %s , %v , want %s , %v", c.hash, kind, err, c.kind, c.err)
+		}
+	}
+}
+
+func TestParseText(t *testing.T) {
+	res, err := ParseText(openFixture(t, "dump.txt"), domain.SignatureKindFunction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []domain.Signature{
+		{Hash: transferHash, Kind: domain.SignatureKindFunction, TextSignature: transferText},
+		{Hash: transferLog, Kind: domain.SignatureKindEvent, TextSignature: "Transfer(address,address,uint256)"},
+		{Kind: domain.SignatureKindFunction, TextSignature: "approve(address,uint256)"},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Fatalf("got %+v\nwant %+v", res, want)
+	}
+}
+
+func TestParseTextError(t *testing.T) {
+	cases := []struct {
+		name        string
+		dump        string
+		defaultKind string
+		want        string
+	}{
+		{"bare signature without kind", "0xa9059cbb transfer(address,uint256)\napprove(address,uint256)", "", "line 2: signature kind unknown"},
+		{"wrong length hash", "# header\n0xa9059c transfer(address,uint256)", domain.SignatureKindFunction, "line 2: invalid signature"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ParseText(strings.NewReader(c.dump), c.defaultKind)
+			if err == nil || err.Error() != c.want {
+				t.Fatalf("err = %v , want %s", err, c.want)
+			}
+		})
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	cases := []struct {
+		fixture string
+		want    []domain.Signature
+	}{
+		{"dump_page.json", []domain.Signature{
+			{Hash: transferHash, Kind: domain.SignatureKindFunction, TextSignature: transferText},
+			{Hash: "0x70a08231", Kind: domain.SignatureKindFunction, TextSignature: "balanceOf(address)"},
+		}},
+		{"dump_array.json", []domain.Signature{
+			{Hash: "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925", Kind: domain.SignatureKindEvent, TextSignature: "Approval(address,address,uint256)"},
+		}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.fixture, func(t *testing.T) {
+			res, err := ParseJSON(openFixture(t, c.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(res, c.want) {
+				t.Fatalf("got %+v\nwant %+v", res, c.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONError(t *testing.T) {
+	_, err := ParseJSON(openFixture(t, "dump_bad_hash.json"))
+	if !errors.Is(err, domain.ErrInvalidSignature) {
+		t.Fatalf("wrong length hash err = %v", err)
+	}
+
+	if _, err = ParseJSON(strings.NewReader(`{"results":"none"}`)); err == nil {
+		t.Fatal("malformed dump accepted")
+	}
+}
+
+func TestParseDir(t *testing.T) {
+	res, err := ParseDir("testdata/4bytes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//files are read in name order , non hash files and sub directories are skipped
+	want := []domain.Signature{
+		{Hash: "0x42966c68", Kind: domain.SignatureKindFunction, TextSignature: "collate_propagate_storage(bytes16)"},
+		{Hash: "0x42966c68", Kind: domain.SignatureKindFunction, TextSignature: "burn(uint256)"},
+		{Hash: transferHash, Kind: domain.SignatureKindFunction, TextSignature: transferText},
+		{Hash: transferLog, Kind: domain.SignatureKindEvent, TextSignature: "Transfer(address,address,uint256)"},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Fatalf("got %+v\nwant %+v", res, want)
+	}
+
+	if _, err = ParseDir("testdata/missing"); err == nil {
+		t.Fatal("missing directory accepted")
+	}
+}
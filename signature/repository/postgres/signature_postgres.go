@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"github.com/ryanCool/ethService/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// saveBatchSize is the number of signatures inserted per statement , big dumps exceed postgres parameter limit otherwise
+const saveBatchSize = 1000
+
+type postgresSignatureRepository struct {
+	Db *gorm.DB
+}
+
+// NewPostgresSignatureRepository will create an object that represent the signature.Repository interface
+func NewPostgresSignatureRepository(db *gorm.DB) domain.SignatureRepository {
+	return &postgresSignatureRepository{db}
+}
+
+// Save insert signatures , known ones are skipped
+func (p *postgresSignatureRepository) Save(ctx context.Context, signatures []domain.Signature) error {
+	return p.Db.Table("eth.signatures").Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&signatures, saveBatchSize).Error
+}
+
+// ListByHashes list signatures of hashes , earliest added first for each hash
+func (p *postgresSignatureRepository) ListByHashes(ctx context.Context, hashes []string) ([]domain.Signature, error) {
+	var res []domain.Signature
+	if err := p.Db.Table("eth.signatures").Where("hash IN ?", hashes).Order("hash, id").Find(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
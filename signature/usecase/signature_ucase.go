@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ryanCool/ethService/domain"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// textSignaturePattern matches canonical text signature like transfer(address,uint256)
+var textSignaturePattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*\([A-Za-z0-9_,()\[\]]*\)$`)
+
+type signatureUseCase struct {
+	repo           domain.SignatureRepository
+	contextTimeout time.Duration
+}
+
+func NewSignatureUseCase(a domain.SignatureRepository, timeout time.Duration) domain.SignatureUseCase {
+	return &signatureUseCase{
+		repo:           a,
+		contextTimeout: timeout,
+	}
+}
+
+// Add hash text signatures of kind and save them , hash is the 4-byte selector for function and topic0 for event
+func (su *signatureUseCase) Add(ctx context.Context, kind string, textSignatures []string) ([]domain.Signature, error) {
+	if kind != domain.SignatureKindFunction && kind != domain.SignatureKindEvent {
+		return nil, domain.ErrInvalidSignature
+	}
+
+	signatures := make([]domain.Signature, 0, len(textSignatures))
+	for _, text := range textSignatures {
+		text = strings.ReplaceAll(text, " ", "")
+		if !textSignaturePattern.MatchString(text) {
+			return nil, domain.ErrInvalidSignature
+		}
+
+		hash := crypto.Keccak256([]byte(text))
+		if kind == domain.SignatureKindFunction {
+			hash = hash[:4]
+		}
+
+		signatures = append(signatures, domain.Signature{
+			Hash:          hexutil.Encode(hash),
+			Kind:          kind,
+			TextSignature: text,
+		})
+	}
+
+	if len(signatures) == 0 {
+		return signatures, nil
+	}
+
+	if err := su.repo.Save(ctx, signatures); err != nil {
+		return nil, err
+	}
+
+	return signatures, nil
+}
+
+func (su *signatureUseCase) ListByHash(ctx context.Context, hash string) ([]domain.Signature, error) {
+	return su.repo.ListByHashes(ctx, []string{strings.ToLower(hash)})
+}
+
+// Lookup returns the earliest added text signature of each known hash
+func (su *signatureUseCase) Lookup(ctx context.Context, hashes []string) (map[string]string, error) {
+	res := map[string]string{}
+	if len(hashes) == 0 {
+		return res, nil
+	}
+
+	signatures, err := su.repo.ListByHashes(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, signature := range signatures {
+		if _, exist := res[signature.Hash]; !exist {
+			res[signature.Hash] = signature.TextSignature
+		}
+	}
+	return res, nil
+}
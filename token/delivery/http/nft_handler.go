@@ -0,0 +1,205 @@
+package http
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/gin-gonic/gin"
+	"github.com/ryanCool/ethService/domain"
+	"github.com/ryanCool/ethService/helper"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ListAddressNftTransfers list nft transfers sent or received by an address , optionally of one token
+func (a *TokenHandler) ListAddressNftTransfers(ctx *gin.Context) {
+	address := ctx.Param("addr")
+	if !common.IsHexAddress(address) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	filter := &domain.NftTransferFilter{
+		Address:   common.HexToAddress(address).String(),
+		Direction: ctx.DefaultQuery("direction", domain.TxDirectionAny),
+	}
+
+	switch filter.Direction {
+	case domain.TxDirectionFrom, domain.TxDirectionTo, domain.TxDirectionAny:
+	default:
+		ctx.JSON(http.StatusBadRequest, "direction should be from, to or any")
+		return
+	}
+
+	if token := ctx.Query("token"); token != "" {
+		if !common.IsHexAddress(token) {
+			helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+			return
+		}
+		filter.Token = common.HexToAddress(token).String()
+	}
+
+	a.listNftTransfers(ctx, filter)
+}
+
+// ListContractNftTransfers list nft transfers of a token contract
+func (a *TokenHandler) ListContractNftTransfers(ctx *gin.Context) {
+	contract := ctx.Param("contract")
+	if !common.IsHexAddress(contract) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	a.listNftTransfers(ctx, &domain.NftTransferFilter{
+		Token: common.HexToAddress(contract).String(),
+	})
+}
+
+// ListTokenIDTransfers list transfers of one token id of a token contract
+func (a *TokenHandler) ListTokenIDTransfers(ctx *gin.Context) {
+	contract := ctx.Param("contract")
+	if !common.IsHexAddress(contract) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	tokenID, err := parseTokenID(ctx.Param("tokenId"))
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	a.listNftTransfers(ctx, &domain.NftTransferFilter{
+		Token:   common.HexToAddress(contract).String(),
+		TokenID: tokenID,
+	})
+}
+
+// GetNftOwner get current owner of an ERC-721 token
+func (a *TokenHandler) GetNftOwner(ctx *gin.Context) {
+	contract := ctx.Param("contract")
+	if !common.IsHexAddress(contract) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	tokenID, err := parseTokenID(ctx.Param("tokenId"))
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	owner, err := a.TUseCase.GetNftOwner(ctx, common.HexToAddress(contract).String(), tokenID)
+	if err == domain.ErrNftNotExist {
+		helper.RespondWithError(ctx, http.StatusNotFound, err)
+		return
+	}
+
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, owner)
+}
+
+// ListAddressNfts list ERC-721 tokens currently owned by an address , latest acquired first
+func (a *TokenHandler) ListAddressNfts(ctx *gin.Context) {
+	address := ctx.Param("addr")
+	if !common.IsHexAddress(address) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	filter := &domain.NftOwnerFilter{
+		Owner: common.HexToAddress(address).String(),
+	}
+
+	if token := ctx.Query("token"); token != "" {
+		if !common.IsHexAddress(token) {
+			helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+			return
+		}
+		filter.Token = common.HexToAddress(token).String()
+	}
+
+	filter.Limit, _ = strconv.Atoi(ctx.Query("limit"))
+	if filter.Limit == 0 {
+		//set default to 20
+		filter.Limit = 20
+	}
+
+	if filter.Limit < 0 || filter.Limit > 100 {
+		ctx.JSON(http.StatusBadRequest, "limit should be 0~100")
+		return
+	}
+
+	if cursor := ctx.Query("cursor"); cursor != "" {
+		position, err := helper.DecodeCursor(cursor, 2)
+		if err != nil {
+			helper.RespondWithError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		filter.After = &domain.LogPosition{BlockNum: position[0], LogIndex: int(position[1])}
+	}
+
+	nfts, err := a.TUseCase.ListNftsByOwner(ctx, filter)
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := map[string]interface{}{"nfts": nfts}
+	if len(nfts) == filter.Limit {
+		last := nfts[len(nfts)-1]
+		res["next"] = helper.EncodeCursor(last.BlockNum, uint64(last.LogIndex))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// listNftTransfers respond one page of nft transfers matching filter
+func (a *TokenHandler) listNftTransfers(ctx *gin.Context, filter *domain.NftTransferFilter) {
+	query, ok := parseListQuery(ctx, 3)
+	if !ok {
+		return
+	}
+
+	filter.Desc, filter.Limit, filter.FromBlock, filter.ToBlock = query.desc, query.limit, query.fromBlock, query.toBlock
+	if query.cursor != nil {
+		filter.After = &domain.NftTransferPosition{
+			BlockNum:   query.cursor[0],
+			LogIndex:   int(query.cursor[1]),
+			BatchIndex: int(query.cursor[2]),
+		}
+	}
+
+	transfers, err := a.TUseCase.ListNftTransfers(ctx, filter)
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := map[string]interface{}{"transfers": transfers}
+	if len(transfers) == filter.Limit {
+		last := transfers[len(transfers)-1]
+		res["next"] = helper.EncodeCursor(last.BlockNum, uint64(last.LogIndex), uint64(last.BatchIndex))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// parseTokenID parse decimal or 0x hex token id into decimal string as stored , leading zeros are decimal
+func parseTokenID(v string) (string, error) {
+	base := 10
+	if strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "0X") {
+		v, base = v[2:], 16
+	}
+
+	id, ok := new(big.Int).SetString(v, base)
+	if !ok || id.Sign() < 0 || id.Cmp(math.MaxBig256) > 0 {
+		return "", domain.ErrInvalidTokenID
+	}
+	return id.String(), nil
+}
@@ -0,0 +1,30 @@
+package http
+
+import (
+	"testing"
+)
+
+func TestParseTokenID(t *testing.T) {
+	cases := []struct {
+		v       string
+		want    string
+		wantErr bool
+	}{
+		{"8520", "8520", false},
+		{"010", "10", false},
+		{"0x2148", "8520", false},
+		{"0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", "115792089237316195423570985008687907853269984665640564039457584007913129639935", false},
+		{"0x1ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", "", true},
+		{"0x", "", true},
+		{"0b101", "", true},
+		{"1_000", "", true},
+		{"-1", "", true},
+	}
+
+	for _, c := range cases {
+		got, err := parseTokenID(c.v)
+		if (err != nil) != c.wantErr || got != c.want {
+			t.Errorf("parseTokenID(%q) = %q , %v , want %q , error %v", c.v, got, err, c.want, c.wantErr)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/ryanCool/ethService/domain"
+	"github.com/ryanCool/ethService/helper"
+	"net/http"
+	"strconv"
+)
+
+// TokenHandler represent the httphandler for token
+type TokenHandler struct {
+	TUseCase domain.TokenUseCase
+}
+
+func NewTokenHandler(e *gin.Engine, tu domain.TokenUseCase) {
+	handler := &TokenHandler{
+		TUseCase: tu,
+	}
+
+	ag := e.Group("address")
+
+	ag.GET("/:addr/token-transfers", handler.ListAddressTransfers)
+	ag.GET("/:addr/nft-transfers", handler.ListAddressNftTransfers)
+	ag.GET("/:addr/nfts", handler.ListAddressNfts)
+
+	tg := e.Group("tokens")
+
+	tg.GET("/:contract", handler.GetToken)
+	tg.GET("/:contract/transfers", handler.ListTokenTransfers)
+	tg.GET("/:contract/nft-transfers", handler.ListContractNftTransfers)
+	tg.GET("/:contract/nfts/:tokenId/transfers", handler.ListTokenIDTransfers)
+	tg.GET("/:contract/nfts/:tokenId/owner", handler.GetNftOwner)
+}
+
+// ListAddressTransfers list token transfers sent or received by an address , optionally of one token
+func (a *TokenHandler) ListAddressTransfers(ctx *gin.Context) {
+	address := ctx.Param("addr")
+	if !common.IsHexAddress(address) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	filter := &domain.TokenTransferFilter{
+		Address:   common.HexToAddress(address).String(),
+		Direction: ctx.DefaultQuery("direction", domain.TxDirectionAny),
+	}
+
+	switch filter.Direction {
+	case domain.TxDirectionFrom, domain.TxDirectionTo, domain.TxDirectionAny:
+	default:
+		ctx.JSON(http.StatusBadRequest, "direction should be from, to or any")
+		return
+	}
+
+	if token := ctx.Query("token"); token != "" {
+		if !common.IsHexAddress(token) {
+			helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+			return
+		}
+		filter.Token = common.HexToAddress(token).String()
+	}
+
+	a.listTransfers(ctx, filter)
+}
+
+// GetToken returns name , symbol , decimals and total supply of a token contract
+func (a *TokenHandler) GetToken(ctx *gin.Context) {
+	contract := ctx.Param("contract")
+	if !common.IsHexAddress(contract) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	token, err := a.TUseCase.GetToken(ctx, common.HexToAddress(contract).String())
+	if err == domain.ErrTokenNotExist {
+		helper.RespondWithError(ctx, http.StatusNotFound, err)
+		return
+	}
+
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, token)
+}
+
+// ListTokenTransfers list transfers of a token contract
+func (a *TokenHandler) ListTokenTransfers(ctx *gin.Context) {
+	contract := ctx.Param("contract")
+	if !common.IsHexAddress(contract) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	a.listTransfers(ctx, &domain.TokenTransferFilter{
+		Token: common.HexToAddress(contract).String(),
+	})
+}
+
+// listTransfers respond one page of transfers matching filter
+func (a *TokenHandler) listTransfers(ctx *gin.Context, filter *domain.TokenTransferFilter) {
+	query, ok := parseListQuery(ctx, 2)
+	if !ok {
+		return
+	}
+
+	filter.Desc, filter.Limit, filter.FromBlock, filter.ToBlock = query.desc, query.limit, query.fromBlock, query.toBlock
+	if query.cursor != nil {
+		filter.After = &domain.LogPosition{BlockNum: query.cursor[0], LogIndex: int(query.cursor[1])}
+	}
+
+	transfers, err := a.TUseCase.ListTransfers(ctx, filter)
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := map[string]interface{}{"transfers": transfers}
+	if len(transfers) == filter.Limit {
+		last := transfers[len(transfers)-1]
+		res["next"] = helper.EncodeCursor(last.BlockNum, uint64(last.LogIndex))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// listQuery is the block range , order and paging query shared by list endpoints
+type listQuery struct {
+	desc      bool
+	limit     int
+	fromBlock uint64
+	toBlock   *uint64
+	cursor    []uint64
+}
+
+// parseListQuery parse list query , cursorLen is the number of values in cursor .
+// Bad request is responded and false returned when query is invalid.
+func parseListQuery(ctx *gin.Context, cursorLen int) (*listQuery, bool) {
+	query := &listQuery{}
+	switch ctx.DefaultQuery("order", "desc") {
+	case "desc":
+		query.desc = true
+	case "asc":
+	default:
+		ctx.JSON(http.StatusBadRequest, "order should be asc or desc")
+		return nil, false
+	}
+
+	query.limit, _ = strconv.Atoi(ctx.Query("limit"))
+	if query.limit == 0 {
+		//set default to 20
+		query.limit = 20
+	}
+
+	if query.limit < 0 || query.limit > 100 {
+		ctx.JSON(http.StatusBadRequest, "limit should be 0~100")
+		return nil, false
+	}
+
+	fromBlock, err := helper.ParseBlockQuery(ctx, "from_block")
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return nil, false
+	}
+	if fromBlock != nil {
+		query.fromBlock = *fromBlock
+	}
+
+	if query.toBlock, err = helper.ParseBlockQuery(ctx, "to_block"); err != nil {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	if cursor := ctx.Query("cursor"); cursor != "" {
+		if query.cursor, err = helper.DecodeCursor(cursor, cursorLen); err != nil {
+			helper.RespondWithError(ctx, http.StatusBadRequest, err)
+			return nil, false
+		}
+	}
+
+	return query, true
+}
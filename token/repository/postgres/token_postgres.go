@@ -0,0 +1,235 @@
+package postgres
+
+import (
+	"context"
+	"github.com/ryanCool/ethService/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresTokenRepository struct {
+	Db *gorm.DB
+}
+
+// NewPostgresTokenRepository will create an object that represent the token.Repository interface
+func NewPostgresTokenRepository(db *gorm.DB) domain.TokenRepository {
+	return &postgresTokenRepository{db}
+}
+
+// SaveTransfers insert token transfers and placeholder of their tokens , transfers already written by a previous try are skipped
+func (p *postgresTokenRepository) SaveTransfers(ctx context.Context, transfers []domain.TokenTransfer) error {
+	addresses := make([]string, 0, len(transfers))
+	for _, transfer := range transfers {
+		addresses = append(addresses, transfer.Token)
+	}
+
+	return p.Db.Transaction(func(tx *gorm.DB) error {
+		if err := saveTokenPlaceholders(tx, addresses); err != nil {
+			return err
+		}
+		return tx.Table("eth.token_transfers").Clauses(clause.OnConflict{DoNothing: true}).Create(&transfers).Error
+	})
+}
+
+func (p *postgresTokenRepository) ListTransfers(ctx context.Context, filter *domain.TokenTransferFilter) ([]domain.TokenTransfer, error) {
+	q := p.Db.Table("eth.token_transfers")
+	if filter.Token != "" {
+		q = q.Where("token = ?", filter.Token)
+	}
+
+	if filter.Address != "" {
+		switch filter.Direction {
+		case domain.TxDirectionFrom:
+			q = q.Where("from_address = ?", filter.Address)
+		case domain.TxDirectionTo:
+			q = q.Where("to_address = ?", filter.Address)
+		default:
+			q = q.Where("(from_address = ? OR to_address = ?)", filter.Address, filter.Address)
+		}
+	}
+
+	q = q.Where("block_num >= ?", filter.FromBlock)
+	if filter.ToBlock != nil {
+		q = q.Where("block_num <= ?", *filter.ToBlock)
+	}
+
+	order := "block_num, log_index"
+	if filter.Desc {
+		order = "block_num desc, log_index desc"
+	}
+
+	if filter.After != nil {
+		if filter.Desc {
+			q = q.Where("(block_num, log_index) < (?, ?)", filter.After.BlockNum, filter.After.LogIndex)
+		} else {
+			q = q.Where("(block_num, log_index) > (?, ?)", filter.After.BlockNum, filter.After.LogIndex)
+		}
+	}
+
+	var res []domain.TokenTransfer
+	if err := q.Order(order).Limit(filter.Limit).Find(&res).Error; err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SaveNftTransfers insert nft transfers and placeholder of their tokens , transfers already written by a previous try are skipped
+func (p *postgresTokenRepository) SaveNftTransfers(ctx context.Context, transfers []domain.NftTransfer) error {
+	addresses := make([]string, 0, len(transfers))
+	for _, transfer := range transfers {
+		addresses = append(addresses, transfer.Token)
+	}
+
+	return p.Db.Transaction(func(tx *gorm.DB) error {
+		if err := saveTokenPlaceholders(tx, addresses); err != nil {
+			return err
+		}
+		return tx.Table("eth.nft_transfers").Clauses(clause.OnConflict{DoNothing: true}).Create(&transfers).Error
+	})
+}
+
+// saveTokenPlaceholders insert unresolved row for tokens not seen before , metadata is read later by eth scan service
+func saveTokenPlaceholders(tx *gorm.DB, addresses []string) error {
+	var tokens []domain.Token
+	seen := map[string]bool{}
+	for _, address := range addresses {
+		if !seen[address] {
+			seen[address] = true
+			tokens = append(tokens, domain.Token{Address: address})
+		}
+	}
+
+	return tx.Table("eth.tokens").Select("address").Clauses(clause.OnConflict{DoNothing: true}).Create(&tokens).Error
+}
+
+func (p *postgresTokenRepository) ListNftTransfers(ctx context.Context, filter *domain.NftTransferFilter) ([]domain.NftTransfer, error) {
+	q := p.Db.Table("eth.nft_transfers")
+	if filter.Token != "" {
+		q = q.Where("token = ?", filter.Token)
+	}
+
+	if filter.TokenID != "" {
+		q = q.Where("token_id = ?", filter.TokenID)
+	}
+
+	if filter.Address != "" {
+		switch filter.Direction {
+		case domain.TxDirectionFrom:
+			q = q.Where("from_address = ?", filter.Address)
+		case domain.TxDirectionTo:
+			q = q.Where("to_address = ?", filter.Address)
+		default:
+			q = q.Where("(from_address = ? OR to_address = ?)", filter.Address, filter.Address)
+		}
+	}
+
+	q = q.Where("block_num >= ?", filter.FromBlock)
+	if filter.ToBlock != nil {
+		q = q.Where("block_num <= ?", *filter.ToBlock)
+	}
+
+	order := "block_num, log_index, batch_index"
+	if filter.Desc {
+		order = "block_num desc, log_index desc, batch_index desc"
+	}
+
+	if filter.After != nil {
+		if filter.Desc {
+			q = q.Where("(block_num, log_index, batch_index) < (?, ?, ?)", filter.After.BlockNum, filter.After.LogIndex, filter.After.BatchIndex)
+		} else {
+			q = q.Where("(block_num, log_index, batch_index) > (?, ?, ?)", filter.After.BlockNum, filter.After.LogIndex, filter.After.BatchIndex)
+		}
+	}
+
+	var res []domain.NftTransfer
+	if err := q.Order(order).Limit(filter.Limit).Find(&res).Error; err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetNftOwner get current owner of an ERC-721 token from eth.erc721_owners view
+func (p *postgresTokenRepository) GetNftOwner(ctx context.Context, token string, tokenID string) (*domain.NftOwner, error) {
+	var res *domain.NftOwner
+	if err := p.Db.Table("eth.erc721_owners").Where("token = ? AND token_id = ?", token, tokenID).First(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListNftsByOwner list ERC-721 tokens whose latest transfer was received by owner
+func (p *postgresTokenRepository) ListNftsByOwner(ctx context.Context, filter *domain.NftOwnerFilter) ([]domain.NftOwner, error) {
+	q := p.Db.Table("eth.nft_transfers t").
+		Select("t.token, t.token_id, t.to_address AS owner, t.block_num, t.log_index").
+		Where("t.to_address = ? AND t.standard = ?", filter.Owner, domain.NftStandardERC721).
+		Where(`NOT EXISTS (SELECT 1 FROM eth.nft_transfers n WHERE n.token = t.token AND n.token_id = t.token_id
+			AND (n.block_num, n.log_index) > (t.block_num, t.log_index))`)
+
+	if filter.Token != "" {
+		q = q.Where("t.token = ?", filter.Token)
+	}
+
+	if filter.After != nil {
+		q = q.Where("(t.block_num, t.log_index) < (?, ?)", filter.After.BlockNum, filter.After.LogIndex)
+	}
+
+	var res []domain.NftOwner
+	if err := q.Order("t.block_num desc, t.log_index desc").Limit(filter.Limit).Scan(&res).Error; err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SaveTokens insert token metadata or replace the fields of existing one , placeholder included
+func (p *postgresTokenRepository) SaveTokens(ctx context.Context, tokens []domain.Token) error {
+	return p.Db.Table("eth.tokens").Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "address"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"name":            gorm.Expr("EXCLUDED.name"),
+			"symbol":          gorm.Expr("EXCLUDED.symbol"),
+			"decimals":        gorm.Expr("EXCLUDED.decimals"),
+			"total_supply":    gorm.Expr("EXCLUDED.total_supply"),
+			"resolved":        gorm.Expr("EXCLUDED.resolved"),
+			"failed_attempts": 0,
+			"updated_at":      gorm.Expr("date_part('epoch'::text, now()) * (1000)::double precision"),
+		}),
+	}).Create(&tokens).Error
+}
+
+// MarkTokensFailed count a failed resolve round of tokens , they are picked after tokens with fewer failures
+func (p *postgresTokenRepository) MarkTokensFailed(ctx context.Context, addresses []string) error {
+	return p.Db.Table("eth.tokens").Where("address IN ?", addresses).
+		Update("failed_attempts", gorm.Expr("failed_attempts + 1")).Error
+}
+
+// ListTokens list resolved tokens of addresses , placeholders are skipped
+func (p *postgresTokenRepository) ListTokens(ctx context.Context, addresses []string) ([]domain.Token, error) {
+	var res []domain.Token
+	if err := p.Db.Table("eth.tokens").Where("address IN ? AND resolved", addresses).Find(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListUnresolvedTokens list placeholders of contracts emitting token transfers without metadata yet ,
+// fewest failed attempts first so tokens failing every round do not hold back new ones , then oldest first
+func (p *postgresTokenRepository) ListUnresolvedTokens(ctx context.Context, limit int) ([]string, error) {
+	var res []string
+	if err := p.Db.Table("eth.tokens").Where("NOT resolved").Order("failed_attempts, created_at").Limit(limit).Pluck("address", &res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListStaleTokens list tokens whose metadata was resolved before updatedBefore (unix milliseconds) ,
+// fewest failed attempts first , then oldest first
+func (p *postgresTokenRepository) ListStaleTokens(ctx context.Context, updatedBefore int64, limit int) ([]string, error) {
+	var res []string
+	if err := p.Db.Table("eth.tokens").Where("resolved AND updated_at < ?", updatedBefore).Order("failed_attempts, updated_at").Limit(limit).Pluck("address", &res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"github.com/ryanCool/ethService/domain"
+	"gorm.io/gorm"
+	"math/big"
+	"strings"
+	"time"
+)
+
+type tokenUseCase struct {
+	repo           domain.TokenRepository
+	contextTimeout time.Duration
+}
+
+func NewTokenUseCase(a domain.TokenRepository, timeout time.Duration) domain.TokenUseCase {
+	return &tokenUseCase{
+		repo:           a,
+		contextTimeout: timeout,
+	}
+}
+
+func (tu *tokenUseCase) SaveTransfers(ctx context.Context, transfers []domain.TokenTransfer) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+	return tu.repo.SaveTransfers(ctx, transfers)
+}
+
+// ListTransfers list transfers with value scaled by decimals of resolved tokens
+func (tu *tokenUseCase) ListTransfers(ctx context.Context, filter *domain.TokenTransferFilter) ([]domain.TokenTransfer, error) {
+	transfers, err := tu.repo.ListTransfers(ctx, filter)
+	if err != nil || len(transfers) == 0 {
+		return transfers, err
+	}
+
+	var addresses []string
+	seen := map[string]bool{}
+	for _, transfer := range transfers {
+		if !seen[transfer.Token] {
+			seen[transfer.Token] = true
+			addresses = append(addresses, transfer.Token)
+		}
+	}
+
+	tokens, err := tu.repo.ListTokens(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	byAddress := map[string]domain.Token{}
+	for _, token := range tokens {
+		byAddress[token.Address] = token
+	}
+
+	for i := range transfers {
+		token, exist := byAddress[transfers[i].Token]
+		if !exist {
+			continue
+		}
+
+		transfers[i].Symbol = token.Symbol
+		if token.Decimals != nil {
+			transfers[i].ValueScaled = scaleAmount(transfers[i].Value, *token.Decimals)
+		}
+	}
+
+	return transfers, nil
+}
+
+func (tu *tokenUseCase) SaveNftTransfers(ctx context.Context, transfers []domain.NftTransfer) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+	return tu.repo.SaveNftTransfers(ctx, transfers)
+}
+
+func (tu *tokenUseCase) ListNftTransfers(ctx context.Context, filter *domain.NftTransferFilter) ([]domain.NftTransfer, error) {
+	return tu.repo.ListNftTransfers(ctx, filter)
+}
+
+func (tu *tokenUseCase) GetNftOwner(ctx context.Context, token string, tokenID string) (*domain.NftOwner, error) {
+	owner, err := tu.repo.GetNftOwner(ctx, token, tokenID)
+	if err == gorm.ErrRecordNotFound {
+		return nil, domain.ErrNftNotExist
+	}
+
+	if err != nil {
+		log.Err(err).Msg("get nft owner fail")
+		return nil, err
+	}
+
+	return owner, nil
+}
+
+func (tu *tokenUseCase) ListNftsByOwner(ctx context.Context, filter *domain.NftOwnerFilter) ([]domain.NftOwner, error) {
+	return tu.repo.ListNftsByOwner(ctx, filter)
+}
+
+func (tu *tokenUseCase) SaveTokens(ctx context.Context, tokens []domain.Token) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	return tu.repo.SaveTokens(ctx, tokens)
+}
+
+func (tu *tokenUseCase) MarkTokensFailed(ctx context.Context, addresses []string) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+	return tu.repo.MarkTokensFailed(ctx, addresses)
+}
+
+func (tu *tokenUseCase) GetToken(ctx context.Context, address string) (*domain.Token, error) {
+	tokens, err := tu.repo.ListTokens(ctx, []string{address})
+	if err != nil {
+		log.Err(err).Msg("get token fail")
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, domain.ErrTokenNotExist
+	}
+
+	return &tokens[0], nil
+}
+
+func (tu *tokenUseCase) ListTokensToResolve(ctx context.Context, staleBefore time.Time, limit int) ([]string, error) {
+	addresses, err := tu.repo.ListUnresolvedTokens(ctx, limit)
+	if err != nil || len(addresses) == limit {
+		return addresses, err
+	}
+
+	stale, err := tu.repo.ListStaleTokens(ctx, staleBefore.UnixMilli(), limit-len(addresses))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(addresses, stale...), nil
+}
+
+// scaleAmount format raw integer amount as decimal number with decimals fraction digits , trailing zeros trimmed
+func scaleAmount(value string, decimals uint8) string {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return ""
+	}
+
+	if decimals == 0 {
+		return amount.String()
+	}
+
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+		amount.Abs(amount)
+	}
+
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	integer, fraction := new(big.Int).QuoRem(amount, unit, new(big.Int))
+	if fraction.Sign() == 0 {
+		return sign + integer.String()
+	}
+
+	digits := fraction.String()
+	digits = strings.Repeat("0", int(decimals)-len(digits)) + digits
+	return sign + integer.String() + "." + strings.TrimRight(digits, "0")
+}
@@ -0,0 +1,196 @@
+package http
+
+import (
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gin-gonic/gin"
+	"github.com/ryanCool/ethService/domain"
+	"github.com/ryanCool/ethService/helper"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// LogHandler  represent the httphandler for log filter
+type LogHandler struct {
+	TUseCase      domain.TransactionUseCase
+	BUseCase      domain.BlockUseCase
+	MaxBlockRange uint64
+}
+
+// logFilterRequest is the filter accepted by log endpoints , omitted block number means latest indexed block
+type logFilterRequest struct {
+	FromBlock *uint64    `json:"from_block"`
+	ToBlock   *uint64    `json:"to_block"`
+	Addresses []string   `json:"addresses"`
+	Topics    [][]string `json:"topics"`
+	Limit     int        `json:"limit"`
+	Cursor    string     `json:"cursor"`
+}
+
+func NewLogHandler(e *gin.Engine, dt domain.TransactionUseCase, db domain.BlockUseCase, maxBlockRange uint64) {
+	handler := &LogHandler{
+		TUseCase:      dt,
+		BUseCase:      db,
+		MaxBlockRange: maxBlockRange,
+	}
+
+	dg := e.Group("logs")
+
+	dg.GET("/", handler.GetLogs)
+	dg.POST("/", handler.PostLogs)
+}
+
+// GetLogs filter logs by query string , address and topicN accept comma separated OR set
+func (a *LogHandler) GetLogs(ctx *gin.Context) {
+	req := &logFilterRequest{Cursor: ctx.Query("cursor")}
+	req.Limit, _ = strconv.Atoi(ctx.Query("limit"))
+
+	var err error
+	if req.FromBlock, err = helper.ParseBlockQuery(ctx, "from_block"); err != nil {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.ToBlock, err = helper.ParseBlockQuery(ctx, "to_block"); err != nil {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	req.Addresses = splitQuery(ctx.QueryArray("address"))
+	for i := 0; i < domain.MaxLogTopics; i++ {
+		req.Topics = append(req.Topics, splitQuery(ctx.QueryArray(fmt.Sprintf("topic%d", i))))
+	}
+
+	a.filterLogs(ctx, req)
+}
+
+// PostLogs filter logs by json body
+func (a *LogHandler) PostLogs(ctx *gin.Context) {
+	req := &logFilterRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	a.filterLogs(ctx, req)
+}
+
+func (a *LogHandler) filterLogs(ctx *gin.Context, req *logFilterRequest) {
+	if req.Limit == 0 {
+		//set default to 100
+		req.Limit = 100
+	}
+
+	if req.Limit < 0 || req.Limit > 1000 {
+		ctx.JSON(http.StatusBadRequest, "limit should be 0~1000")
+		return
+	}
+
+	filter, err := toLogFilter(req)
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	//omitted block number means latest indexed block
+	if req.FromBlock == nil || req.ToBlock == nil {
+		latest, err := a.BUseCase.List(ctx, domain.NewLatestBlockFilter())
+		if err != nil {
+			helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		var latestNum uint64
+		if len(latest) > 0 {
+			latestNum = latest[0].BlockNum
+		}
+		if req.FromBlock == nil {
+			filter.FromBlock = latestNum
+		}
+		if req.ToBlock == nil {
+			filter.ToBlock = latestNum
+		}
+	}
+
+	if filter.ToBlock >= filter.FromBlock && filter.ToBlock-filter.FromBlock >= a.MaxBlockRange {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrBlockRangeTooLarge)
+		return
+	}
+
+	logs, err := a.TUseCase.FilterLogs(ctx, filter)
+	if err == domain.ErrInvalidLogFilter {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := map[string]interface{}{"logs": logs}
+	if len(logs) == filter.Limit {
+		last := logs[len(logs)-1]
+		res["next"] = helper.EncodeCursor(last.BlockNum, uint64(last.LogIndex))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// toLogFilter validate request and normalize addresses and topics to the stored format
+func toLogFilter(req *logFilterRequest) (*domain.LogFilter, error) {
+	filter := &domain.LogFilter{Limit: req.Limit}
+	if req.FromBlock != nil {
+		filter.FromBlock = *req.FromBlock
+	}
+	if req.ToBlock != nil {
+		filter.ToBlock = *req.ToBlock
+	}
+
+	for _, address := range req.Addresses {
+		if !common.IsHexAddress(address) {
+			return nil, domain.ErrInvalidLogFilter
+		}
+		filter.Addresses = append(filter.Addresses, common.HexToAddress(address).String())
+	}
+
+	if len(req.Topics) > domain.MaxLogTopics {
+		return nil, domain.ErrInvalidLogFilter
+	}
+	for _, set := range req.Topics {
+		var topics []string
+		for _, topic := range set {
+			b, err := hexutil.Decode(topic)
+			if err != nil || len(b) != common.HashLength {
+				return nil, domain.ErrInvalidLogFilter
+			}
+			topics = append(topics, common.BytesToHash(b).String())
+		}
+		filter.Topics = append(filter.Topics, topics)
+	}
+
+	if req.Cursor != "" {
+		position, err := helper.DecodeCursor(req.Cursor, 2)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = &domain.LogPosition{BlockNum: position[0], LogIndex: int(position[1])}
+	}
+
+	return filter, nil
+}
+
+// splitQuery flatten repeated and comma separated query values
+func splitQuery(values []string) []string {
+	var res []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				res = append(res, item)
+			}
+		}
+	}
+	return res
+}
@@ -1,10 +1,12 @@
 package http
 
 import (
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 	"github.com/ryanCool/ethService/domain"
 	"github.com/ryanCool/ethService/helper"
 	"net/http"
+	"strconv"
 )
 
 // TransactionHandler  represent the httphandler for article
@@ -20,6 +22,155 @@ func NewTransactionHandler(e *gin.Engine, dt domain.TransactionUseCase) {
 	dg := e.Group("transaction")
 
 	dg.GET("/:txHash", handler.GetTransaction)
+
+	ag := e.Group("address")
+
+	ag.GET("/:addr/transactions", handler.ListAddressTransactions)
+	ag.GET("/:addr/internal-transactions", handler.ListAddressInternalTransactions)
+}
+
+// ListAddressTransactions list transactions sent or received by an address
+func (a *TransactionHandler) ListAddressTransactions(ctx *gin.Context) {
+	query, ok := parseAddressQuery(ctx, 2)
+	if !ok {
+		return
+	}
+
+	filter := &domain.AddressTxFilter{
+		Address:   query.address,
+		Direction: query.direction,
+		FromBlock: query.fromBlock,
+		ToBlock:   query.toBlock,
+		Desc:      query.desc,
+		Limit:     query.limit,
+	}
+	if query.cursor != nil {
+		filter.After = &domain.TxPosition{BlockNum: query.cursor[0], TxIndex: uint(query.cursor[1])}
+	}
+
+	transactions, err := a.TUseCase.ListByAddress(ctx, filter)
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := map[string]interface{}{"transactions": transactions}
+	if len(transactions) == filter.Limit {
+		last := transactions[len(transactions)-1]
+		res["next"] = helper.EncodeCursor(last.BlockNum, uint64(last.TxIndex))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ListAddressInternalTransactions list nested calls made or received by an address
+func (a *TransactionHandler) ListAddressInternalTransactions(ctx *gin.Context) {
+	query, ok := parseAddressQuery(ctx, 3)
+	if !ok {
+		return
+	}
+
+	filter := &domain.InternalTxFilter{
+		Address:   query.address,
+		Direction: query.direction,
+		FromBlock: query.fromBlock,
+		ToBlock:   query.toBlock,
+		Desc:      query.desc,
+		Limit:     query.limit,
+	}
+	if query.cursor != nil {
+		filter.After = &domain.InternalTxPosition{BlockNum: query.cursor[0], TxIndex: uint(query.cursor[1]), TraceIndex: int(query.cursor[2])}
+	}
+
+	internalTransactions, err := a.TUseCase.ListInternalByAddress(ctx, filter)
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := map[string]interface{}{"internal_transactions": internalTransactions}
+	if len(internalTransactions) == filter.Limit {
+		last := internalTransactions[len(internalTransactions)-1]
+		res["next"] = helper.EncodeCursor(last.BlockNum, uint64(last.TxIndex), uint64(last.TraceIndex))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// addressQuery is the address , direction , block range , order and paging query of address history endpoints
+type addressQuery struct {
+	address   string
+	direction string
+	desc      bool
+	limit     int
+	fromBlock uint64
+	toBlock   *uint64
+	cursor    []uint64
+}
+
+// parseAddressQuery parse address history query , cursorLen is the number of values in cursor .
+// Bad request is responded and false returned when query is invalid.
+func parseAddressQuery(ctx *gin.Context, cursorLen int) (*addressQuery, bool) {
+	address := ctx.Param("addr")
+	if !common.IsHexAddress(address) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return nil, false
+	}
+
+	query := &addressQuery{
+		address:   common.HexToAddress(address).String(),
+		direction: ctx.DefaultQuery("direction", domain.TxDirectionAny),
+	}
+
+	switch query.direction {
+	case domain.TxDirectionFrom, domain.TxDirectionTo, domain.TxDirectionAny:
+	default:
+		ctx.JSON(http.StatusBadRequest, "direction should be from, to or any")
+		return nil, false
+	}
+
+	switch ctx.DefaultQuery("order", "desc") {
+	case "desc":
+		query.desc = true
+	case "asc":
+	default:
+		ctx.JSON(http.StatusBadRequest, "order should be asc or desc")
+		return nil, false
+	}
+
+	query.limit, _ = strconv.Atoi(ctx.Query("limit"))
+	if query.limit == 0 {
+		//set default to 20
+		query.limit = 20
+	}
+
+	if query.limit < 0 || query.limit > 100 {
+		ctx.JSON(http.StatusBadRequest, "limit should be 0~100")
+		return nil, false
+	}
+
+	fromBlock, err := helper.ParseBlockQuery(ctx, "from_block")
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return nil, false
+	}
+	if fromBlock != nil {
+		query.fromBlock = *fromBlock
+	}
+
+	if query.toBlock, err = helper.ParseBlockQuery(ctx, "to_block"); err != nil {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	if cursor := ctx.Query("cursor"); cursor != "" {
+		if query.cursor, err = helper.DecodeCursor(cursor, cursorLen); err != nil {
+			helper.RespondWithError(ctx, http.StatusBadRequest, err)
+			return nil, false
+		}
+	}
+
+	return query, true
 }
 
 func (a *TransactionHandler) GetTransaction(ctx *gin.Context) {
@@ -28,6 +179,7 @@ func (a *TransactionHandler) GetTransaction(ctx *gin.Context) {
 	transaction, err := a.TUseCase.GetByTxHash(ctx, txHash)
 	if err == domain.ErrTransactionNotExist {
 		helper.RespondWithError(ctx, http.StatusNotFound, err)
+		return
 	}
 
 	if err != nil {
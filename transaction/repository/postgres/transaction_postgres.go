@@ -6,6 +6,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// internalTxBatchSize is the number of internal transactions inserted per statement , traced blocks can have thousands of frames
+const internalTxBatchSize = 1000
+
 type postgresTransactionRepository struct {
 	Db *gorm.DB
 }
@@ -15,13 +18,38 @@ func NewPostgresTransactionRepository(db *gorm.DB) domain.TransactionRepository
 	return &postgresTransactionRepository{db}
 }
 
+// Create insert transaction with its access list in one transaction
 func (p *postgresTransactionRepository) Create(ctx context.Context, transaction *domain.Transaction) error {
-	return p.Db.Table("eth.transactions").Create(&transaction).Error
+	return p.Db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("eth.transactions").Create(&transaction).Error; err != nil {
+			return err
+		}
+
+		if len(transaction.AccessList) == 0 {
+			return nil
+		}
+
+		if err := tx.Table("eth.access_lists").Create(&transaction.AccessList).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// ListAccessLists list access list entries of transactions in entry order
+func (p *postgresTransactionRepository) ListAccessLists(ctx context.Context, txHashes []string) ([]domain.AccessListEntry, error) {
+	var res []domain.AccessListEntry
+	if err := p.Db.Table("eth.access_lists").Where("tx_hash IN ?", txHashes).Order("tx_hash, entry_index").Find(&res).Error; err != nil {
+		return nil, err
+	}
+
+	return res, nil
 }
 
 func (p *postgresTransactionRepository) GetTxHashesByBlockHash(ctx context.Context, blockHash string) ([]string, error) {
 	var res []domain.Transaction
-	if err := p.Db.Select("tx_hash").Table("eth.transactions").Where("block_hash = ?", blockHash).Find(&res).Error; err != nil {
+	if err := p.Db.Select("tx_hash").Table("eth.transactions").Where("block_hash = ?", blockHash).Order("tx_index").Find(&res).Error; err != nil {
 		return nil, err
 	}
 
@@ -33,13 +61,26 @@ func (p *postgresTransactionRepository) GetTxHashesByBlockHash(ctx context.Conte
 	return hashes, nil
 }
 
-func (p *postgresTransactionRepository) SaveReceiptAndLogs(ctx context.Context, txHash string, logs []domain.TransactionLog) error {
+func (p *postgresTransactionRepository) ListByBlockHash(ctx context.Context, blockHash string) ([]domain.Transaction, error) {
+	var res []domain.Transaction
+	if err := p.Db.Table("eth.transactions").Where("block_hash = ?", blockHash).Order("tx_index").Find(&res).Error; err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (p *postgresTransactionRepository) SaveReceiptAndLogs(ctx context.Context, receipt *domain.Receipt, logs []domain.TransactionLog) error {
 	return p.Db.Transaction(func(tx *gorm.DB) error {
 
-		if err := tx.Table("eth.receipts").Create(&domain.Receipt{TxHash: txHash}).Error; err != nil {
+		if err := tx.Table("eth.receipts").Create(&receipt).Error; err != nil {
 			return err
 		}
 
+		if len(logs) == 0 {
+			return nil
+		}
+
 		if err := tx.Table("eth.transaction_logs").Create(&logs).Error; err != nil {
 			return err
 		}
@@ -48,6 +89,15 @@ func (p *postgresTransactionRepository) SaveReceiptAndLogs(ctx context.Context,
 	})
 }
 
+func (p *postgresTransactionRepository) GetReceiptByTxHash(ctx context.Context, txHash string) (*domain.Receipt, error) {
+	var res *domain.Receipt
+	if err := p.Db.Table("eth.receipts").Where("tx_hash = ?", txHash).First(&res).Error; err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 func (p *postgresTransactionRepository) GetLogsByTxHash(ctx context.Context, txHash string) ([]domain.TransactionLog, error) {
 	var res []domain.TransactionLog
 	if err := p.Db.Table("eth.transaction_logs").Where("tx_hash = ?", txHash).Find(&res).Error; err != nil {
@@ -57,6 +107,72 @@ func (p *postgresTransactionRepository) GetLogsByTxHash(ctx context.Context, txH
 	return res, nil
 }
 
+// logTopicColumns are topic columns by position
+var logTopicColumns = []string{"topic0", "topic1", "topic2", "topic3"}
+
+// FilterLogs list logs matching filter ordered by position in chain
+func (p *postgresTransactionRepository) FilterLogs(ctx context.Context, filter *domain.LogFilter) ([]domain.TransactionLog, error) {
+	q := p.Db.Table("eth.transaction_logs").Where("block_num BETWEEN ? AND ?", filter.FromBlock, filter.ToBlock)
+	if len(filter.Addresses) > 0 {
+		q = q.Where("address IN ?", filter.Addresses)
+	}
+
+	for i, topics := range filter.Topics {
+		if len(topics) > 0 {
+			q = q.Where(logTopicColumns[i]+" IN ?", topics)
+		}
+	}
+
+	if filter.After != nil {
+		q = q.Where("(block_num, log_index) > (?, ?)", filter.After.BlockNum, filter.After.LogIndex)
+	}
+
+	var res []domain.TransactionLog
+	if err := q.Order("block_num, log_index").Limit(filter.Limit).Find(&res).Error; err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListByAddress list transactions of an address ordered by position in chain
+func (p *postgresTransactionRepository) ListByAddress(ctx context.Context, filter *domain.AddressTxFilter) ([]domain.Transaction, error) {
+	q := p.Db.Table("eth.transactions")
+	switch filter.Direction {
+	case domain.TxDirectionFrom:
+		q = q.Where("tx_from = ?", filter.Address)
+	case domain.TxDirectionTo:
+		q = q.Where("tx_to = ?", filter.Address)
+	default:
+		q = q.Where("(tx_from = ? OR tx_to = ?)", filter.Address, filter.Address)
+	}
+
+	q = q.Where("block_num >= ?", filter.FromBlock)
+	if filter.ToBlock != nil {
+		q = q.Where("block_num <= ?", *filter.ToBlock)
+	}
+
+	order := "block_num, tx_index"
+	if filter.Desc {
+		order = "block_num desc, tx_index desc"
+	}
+
+	if filter.After != nil {
+		if filter.Desc {
+			q = q.Where("(block_num, tx_index) < (?, ?)", filter.After.BlockNum, filter.After.TxIndex)
+		} else {
+			q = q.Where("(block_num, tx_index) > (?, ?)", filter.After.BlockNum, filter.After.TxIndex)
+		}
+	}
+
+	var res []domain.Transaction
+	if err := q.Order(order).Limit(filter.Limit).Find(&res).Error; err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 func (p *postgresTransactionRepository) GetByTxHash(ctx context.Context, txHash string) (*domain.Transaction, error) {
 	var res *domain.Transaction
 	if err := p.Db.Table("eth.transactions").Where("tx_hash = ?", txHash).First(&res).Error; err != nil {
@@ -65,3 +181,56 @@ func (p *postgresTransactionRepository) GetByTxHash(ctx context.Context, txHash
 
 	return res, nil
 }
+
+// SaveInternalTransactions insert internal transactions of traced transactions
+func (p *postgresTransactionRepository) SaveInternalTransactions(ctx context.Context, internalTransactions []domain.InternalTransaction) error {
+	return p.Db.Table("eth.internal_transactions").CreateInBatches(&internalTransactions, internalTxBatchSize).Error
+}
+
+// ListInternalTransactions list call frames of transaction in call tree order
+func (p *postgresTransactionRepository) ListInternalTransactions(ctx context.Context, txHash string) ([]domain.InternalTransaction, error) {
+	var res []domain.InternalTransaction
+	if err := p.Db.Table("eth.internal_transactions").Where("tx_hash = ?", txHash).Order("trace_index").Find(&res).Error; err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListInternalByAddress list nested calls of an address ordered by position in chain
+func (p *postgresTransactionRepository) ListInternalByAddress(ctx context.Context, filter *domain.InternalTxFilter) ([]domain.InternalTransaction, error) {
+	q := p.Db.Table("eth.internal_transactions").Where("depth > 0")
+	switch filter.Direction {
+	case domain.TxDirectionFrom:
+		q = q.Where("from_address = ?", filter.Address)
+	case domain.TxDirectionTo:
+		q = q.Where("to_address = ?", filter.Address)
+	default:
+		q = q.Where("(from_address = ? OR to_address = ?)", filter.Address, filter.Address)
+	}
+
+	q = q.Where("block_num >= ?", filter.FromBlock)
+	if filter.ToBlock != nil {
+		q = q.Where("block_num <= ?", *filter.ToBlock)
+	}
+
+	order := "block_num, tx_index, trace_index"
+	if filter.Desc {
+		order = "block_num desc, tx_index desc, trace_index desc"
+	}
+
+	if filter.After != nil {
+		if filter.Desc {
+			q = q.Where("(block_num, tx_index, trace_index) < (?, ?, ?)", filter.After.BlockNum, filter.After.TxIndex, filter.After.TraceIndex)
+		} else {
+			q = q.Where("(block_num, tx_index, trace_index) > (?, ?, ?)", filter.After.BlockNum, filter.After.TxIndex, filter.After.TraceIndex)
+		}
+	}
+
+	var res []domain.InternalTransaction
+	if err := q.Order(order).Limit(filter.Limit).Find(&res).Error; err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
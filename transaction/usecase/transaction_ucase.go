@@ -2,19 +2,23 @@ package usecase
 
 import (
 	"context"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ryanCool/ethService/domain"
+	"gorm.io/gorm"
 	"time"
 )
 
 type transactionUseCase struct {
 	repo             domain.TransactionRepository
 	transactionUcase domain.TransactionUseCase
+	signatureUcase   domain.SignatureUseCase
 	contextTimeout   time.Duration
 }
 
-func NewTransactionUseCase(a domain.TransactionRepository, timeout time.Duration) domain.TransactionUseCase {
+func NewTransactionUseCase(a domain.TransactionRepository, su domain.SignatureUseCase, timeout time.Duration) domain.TransactionUseCase {
 	return &transactionUseCase{
 		repo:           a,
+		signatureUcase: su,
 		contextTimeout: timeout,
 	}
 }
@@ -25,9 +29,26 @@ func (tu *transactionUseCase) Create(ctx context.Context, transaction *domain.Tr
 
 func (tu *transactionUseCase) GetByTxHash(ctx context.Context, txHash string) (*domain.Transaction, error) {
 	l, err := tu.repo.GetByTxHash(ctx, txHash)
+	if err == gorm.ErrRecordNotFound {
+		return nil, domain.ErrTransactionNotExist
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	accessList, err := tu.repo.ListAccessLists(ctx, []string{txHash})
 	if err != nil {
 		return nil, err
 	}
+	l.AccessList = accessList
+
+	//receipt may not be written yet
+	receipt, err := tu.repo.GetReceiptByTxHash(ctx, txHash)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	l.Receipt = receipt
 
 	logs, err := tu.repo.GetLogsByTxHash(ctx, txHash)
 	if err != nil {
@@ -35,13 +56,121 @@ func (tu *transactionUseCase) GetByTxHash(ctx context.Context, txHash string) (*
 	}
 	l.Logs = logs
 
-	return l, nil
+	internalTransactions, err := tu.repo.ListInternalTransactions(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	l.InternalTransactions = internalTransactions
+
+	transactions := []domain.Transaction{*l}
+	if err := tu.annotate(ctx, transactions, l.Logs); err != nil {
+		return nil, err
+	}
+
+	return &transactions[0], nil
+}
+
+func (tu *transactionUseCase) FilterLogs(ctx context.Context, filter *domain.LogFilter) ([]domain.TransactionLog, error) {
+	if filter.FromBlock > filter.ToBlock || len(filter.Topics) > domain.MaxLogTopics {
+		return nil, domain.ErrInvalidLogFilter
+	}
+
+	logs, err := tu.repo.FilterLogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return logs, tu.annotate(ctx, nil, logs)
+}
+
+func (tu *transactionUseCase) ListByAddress(ctx context.Context, filter *domain.AddressTxFilter) ([]domain.Transaction, error) {
+	transactions, err := tu.repo.ListByAddress(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, tu.annotate(ctx, transactions, nil)
+}
+
+// SaveInternalTransactions save call frames of traced transactions , nothing to do for empty block
+func (tu *transactionUseCase) SaveInternalTransactions(ctx context.Context, internalTransactions []domain.InternalTransaction) error {
+	if len(internalTransactions) == 0 {
+		return nil
+	}
+
+	return tu.repo.SaveInternalTransactions(ctx, internalTransactions)
+}
+
+func (tu *transactionUseCase) ListInternalByAddress(ctx context.Context, filter *domain.InternalTxFilter) ([]domain.InternalTransaction, error) {
+	return tu.repo.ListInternalByAddress(ctx, filter)
 }
 
 func (tu *transactionUseCase) GetTxHashesByBlockHash(ctx context.Context, blockHash string) ([]string, error) {
 	return tu.repo.GetTxHashesByBlockHash(ctx, blockHash)
 }
 
-func (tu *transactionUseCase) SaveReceiptAndLogs(ctx context.Context, txHash string, logs []domain.TransactionLog) error {
-	return tu.repo.SaveReceiptAndLogs(ctx, txHash, logs)
+// ListByBlockHash list transactions of block with their access lists
+func (tu *transactionUseCase) ListByBlockHash(ctx context.Context, blockHash string) ([]domain.Transaction, error) {
+	transactions, err := tu.repo.ListByBlockHash(ctx, blockHash)
+	if err != nil || len(transactions) == 0 {
+		return transactions, err
+	}
+
+	txHashes := make([]string, 0, len(transactions))
+	for _, transaction := range transactions {
+		txHashes = append(txHashes, transaction.TxHash)
+	}
+
+	entries, err := tu.repo.ListAccessLists(ctx, txHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	byTxHash := map[string][]domain.AccessListEntry{}
+	for _, entry := range entries {
+		byTxHash[entry.TxHash] = append(byTxHash[entry.TxHash], entry)
+	}
+	for i := range transactions {
+		transactions[i].AccessList = byTxHash[transactions[i].TxHash]
+	}
+
+	return transactions, nil
+}
+
+func (tu *transactionUseCase) SaveReceiptAndLogs(ctx context.Context, receipt *domain.Receipt, logs []domain.TransactionLog) error {
+	return tu.repo.SaveReceiptAndLogs(ctx, receipt, logs)
+}
+
+// annotate set method of transactions and event of logs from signature table , unknown ones are left empty
+func (tu *transactionUseCase) annotate(ctx context.Context, transactions []domain.Transaction, logs []domain.TransactionLog) error {
+	var hashes []string
+	for _, transaction := range transactions {
+		if len(transaction.TxData) >= 4 {
+			hashes = append(hashes, hexutil.Encode(transaction.TxData[:4]))
+		}
+	}
+	for _, l := range logs {
+		if l.Topic0 != "" {
+			hashes = append(hashes, l.Topic0)
+		}
+	}
+
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	known, err := tu.signatureUcase.Lookup(ctx, hashes)
+	if err != nil {
+		return err
+	}
+
+	for i := range transactions {
+		if len(transactions[i].TxData) >= 4 {
+			transactions[i].Method = known[hexutil.Encode(transactions[i].TxData[:4])]
+		}
+	}
+	for i := range logs {
+		logs[i].Event = known[logs[i].Topic0]
+	}
+	return nil
 }
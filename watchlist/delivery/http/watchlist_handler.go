@@ -0,0 +1,159 @@
+package http
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/ryanCool/ethService/domain"
+	"github.com/ryanCool/ethService/helper"
+	"net/http"
+	"strconv"
+)
+
+// WatchlistHandler represent the httphandler for watchlist and native balance history
+type WatchlistHandler struct {
+	WUseCase domain.WatchlistUseCase
+}
+
+func NewWatchlistHandler(e *gin.Engine, wu domain.WatchlistUseCase, adminToken string) {
+	handler := &WatchlistHandler{
+		WUseCase: wu,
+	}
+
+	wg := e.Group("watchlist")
+
+	wg.GET("/", handler.ListWatched)
+	wg.POST("/", helper.AdminAuthMiddleware(adminToken), handler.AddWatched)
+	wg.DELETE("/:addr", helper.AdminAuthMiddleware(adminToken), handler.RemoveWatched)
+
+	ag := e.Group("address")
+
+	ag.GET("/:addr/balance-history", handler.ListBalanceHistory)
+}
+
+func (a *WatchlistHandler) ListWatched(ctx *gin.Context) {
+	watched, err := a.WUseCase.List(ctx)
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{"watchlist": watched})
+}
+
+// AddWatched add address to watchlist , label of an already watched address is replaced
+func (a *WatchlistHandler) AddWatched(ctx *gin.Context) {
+	watched := &domain.WatchedAddress{}
+	if err := ctx.ShouldBindJSON(watched); err != nil {
+		helper.RespondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if !common.IsHexAddress(watched.Address) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+	watched.Address = common.HexToAddress(watched.Address).String()
+
+	if err := a.WUseCase.Add(ctx, watched); err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, watched)
+}
+
+func (a *WatchlistHandler) RemoveWatched(ctx *gin.Context) {
+	address := ctx.Param("addr")
+	if !common.IsHexAddress(address) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	err := a.WUseCase.Remove(ctx, common.HexToAddress(address).String())
+	if err == domain.ErrWatchedNotExist {
+		helper.RespondWithError(ctx, http.StatusNotFound, err)
+		return
+	}
+
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListBalanceHistory list native balance snapshots of a watched address as time series
+func (a *WatchlistHandler) ListBalanceHistory(ctx *gin.Context) {
+	address := ctx.Param("addr")
+	if !common.IsHexAddress(address) {
+		helper.RespondWithError(ctx, http.StatusBadRequest, domain.ErrInvalidAddress)
+		return
+	}
+
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+	if limit == 0 {
+		//set default to 20
+		limit = 20
+	}
+
+	if limit < 0 || limit > 100 {
+		ctx.JSON(http.StatusBadRequest, "limit should be 0~100")
+		return
+	}
+
+	filter := &domain.SnapshotFilter{
+		Address: common.HexToAddress(address).String(),
+		Limit:   limit,
+	}
+	switch ctx.DefaultQuery("order", "asc") {
+	case "desc":
+		filter.Desc = true
+	case "asc":
+	default:
+		ctx.JSON(http.StatusBadRequest, "order should be asc or desc")
+		return
+	}
+
+	bounds := map[string]**uint64{
+		"from_block": &filter.FromBlock,
+		"to_block":   &filter.ToBlock,
+		"from_time":  &filter.FromTime,
+		"to_time":    &filter.ToTime,
+	}
+	for key, bound := range bounds {
+		v := ctx.Query(key)
+		if v == "" {
+			continue
+		}
+
+		n, err := helper.ParseUint(v)
+		if err != nil {
+			helper.RespondWithError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		*bound = &n
+	}
+
+	if cursor := ctx.Query("cursor"); cursor != "" {
+		position, err := helper.DecodeCursor(cursor, 1)
+		if err != nil {
+			helper.RespondWithError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		filter.AfterBlock = &position[0]
+	}
+
+	snapshots, err := a.WUseCase.ListSnapshots(ctx, filter)
+	if err != nil {
+		helper.RespondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := map[string]interface{}{"address": filter.Address, "snapshots": snapshots}
+	if len(snapshots) == filter.Limit {
+		res["next"] = helper.EncodeCursor(snapshots[len(snapshots)-1].BlockNum)
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"github.com/ryanCool/ethService/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresWatchlistRepository struct {
+	Db *gorm.DB
+}
+
+// NewPostgresWatchlistRepository will create an object that represent the watchlist.Repository interface
+func NewPostgresWatchlistRepository(db *gorm.DB) domain.WatchlistRepository {
+	return &postgresWatchlistRepository{db}
+}
+
+// Add insert watched address or update label of the existing one
+func (p *postgresWatchlistRepository) Add(ctx context.Context, watched *domain.WatchedAddress) error {
+	return p.Db.Table("eth.watchlist").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "address"}},
+		DoUpdates: clause.AssignmentColumns([]string{"label"}),
+	}).Create(&watched).Error
+}
+
+func (p *postgresWatchlistRepository) Remove(ctx context.Context, address string) error {
+	d := p.Db.Table("eth.watchlist").Where("address = ?", address).Delete(&domain.WatchedAddress{})
+	if d.Error != nil {
+		return d.Error
+	}
+
+	if d.RowsAffected != 1 {
+		return domain.ErrWatchedNotExist
+	}
+
+	return nil
+}
+
+func (p *postgresWatchlistRepository) List(ctx context.Context) ([]domain.WatchedAddress, error) {
+	var res []domain.WatchedAddress
+	if err := p.Db.Table("eth.watchlist").Order("address").Find(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *postgresWatchlistRepository) SaveSnapshots(ctx context.Context, snapshots []domain.BalanceSnapshot) error {
+	return p.Db.Table("eth.balance_snapshots").Create(&snapshots).Error
+}
+
+func (p *postgresWatchlistRepository) ListSnapshots(ctx context.Context, filter *domain.SnapshotFilter) ([]domain.BalanceSnapshot, error) {
+	q := p.Db.Table("eth.balance_snapshots").Where("address = ?", filter.Address)
+	if filter.FromBlock != nil {
+		q = q.Where("block_num >= ?", *filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		q = q.Where("block_num <= ?", *filter.ToBlock)
+	}
+	if filter.FromTime != nil {
+		q = q.Where("block_time >= ?", *filter.FromTime)
+	}
+	if filter.ToTime != nil {
+		q = q.Where("block_time <= ?", *filter.ToTime)
+	}
+
+	order := "block_num"
+	if filter.Desc {
+		order = "block_num desc"
+	}
+
+	if filter.AfterBlock != nil {
+		if filter.Desc {
+			q = q.Where("block_num < ?", *filter.AfterBlock)
+		} else {
+			q = q.Where("block_num > ?", *filter.AfterBlock)
+		}
+	}
+
+	var res []domain.BalanceSnapshot
+	if err := q.Limit(filter.Limit).Order(order).Find(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"github.com/ryanCool/ethService/domain"
+	"time"
+)
+
+type watchlistUseCase struct {
+	repo           domain.WatchlistRepository
+	contextTimeout time.Duration
+}
+
+func NewWatchlistUseCase(a domain.WatchlistRepository, timeout time.Duration) domain.WatchlistUseCase {
+	return &watchlistUseCase{
+		repo:           a,
+		contextTimeout: timeout,
+	}
+}
+
+func (wu *watchlistUseCase) Add(ctx context.Context, watched *domain.WatchedAddress) error {
+	return wu.repo.Add(ctx, watched)
+}
+
+func (wu *watchlistUseCase) Remove(ctx context.Context, address string) error {
+	return wu.repo.Remove(ctx, address)
+}
+
+func (wu *watchlistUseCase) List(ctx context.Context) ([]domain.WatchedAddress, error) {
+	return wu.repo.List(ctx)
+}
+
+func (wu *watchlistUseCase) SaveSnapshots(ctx context.Context, snapshots []domain.BalanceSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	return wu.repo.SaveSnapshots(ctx, snapshots)
+}
+
+func (wu *watchlistUseCase) ListSnapshots(ctx context.Context, filter *domain.SnapshotFilter) ([]domain.BalanceSnapshot, error) {
+	return wu.repo.ListSnapshots(ctx, filter)
+}